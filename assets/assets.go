@@ -0,0 +1,10 @@
+// Package assets holds the stock banners compiled into the binaries
+package assets
+
+import "embed"
+
+// Banners contains the stock banner files, e.g. "standard.txt", and their
+// glyph overrides, e.g. "standard.overrides"
+//
+//go:embed *.txt *.overrides
+var Banners embed.FS
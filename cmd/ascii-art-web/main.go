@@ -3,28 +3,91 @@ package main
 import (
 	"ascii-art/internal/ascii"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 )
 
+// fonts caches the banners found in the user font directories and among the
+// stock banners built into the binary. Handlers share it, so every banner is
+// parsed once rather than on each request.
+var fonts = ascii.NewRegistry(ascii.DefaultResolver(), false)
+
 type Request struct {
 	Text      string `json:"text"`
 	Banner    string `json:"banner"`
 	Color     string `json:"color,omitempty"`
 	Substring string `json:"substring,omitempty"`
 	Align     string `json:"align,omitempty"`
+	Width     *int   `json:"width,omitempty"`
+	Layout    string `json:"layout,omitempty"`
+	Fallback  string `json:"fallback,omitempty"`
+	Output    string `json:"output,omitempty"`
+	Format    string `json:"format,omitempty"`
+
+	Gradient      string  `json:"gradient,omitempty"`
+	GradientAngle float64 `json:"gradient_angle,omitempty"`
+	GradientSpace string  `json:"gradient_space,omitempty"`
+	Palette       string  `json:"palette,omitempty"`
+	Rules         []Rule  `json:"rules,omitempty"`
+	Bg            string  `json:"bg,omitempty"`
+	Attr          string  `json:"attr,omitempty"`
+	Match         string  `json:"match,omitempty"`
+	IgnoreCase    bool    `json:"ignore_case,omitempty"`
+	Wrap          string  `json:"wrap,omitempty"`
+	Hyphenate     bool    `json:"hyphenate,omitempty"`
+	Canvas        string  `json:"canvas,omitempty"`
+	VAlign        string  `json:"valign,omitempty"`
+	Padding       string  `json:"padding,omitempty"`
+	Margin        string  `json:"margin,omitempty"`
+}
+
+// Rule colors every occurrence of a pattern; a pattern between slashes is a
+// regular expression. Later rules win where matches overlap.
+type Rule struct {
+	Pattern string `json:"pattern"`
+	Color   string `json:"color"`
 }
 
+// Result formats
+const (
+	formatText = "text" // plain text without colors
+	formatHTML = "html" // escaped HTML with colored <span> runs, for a <pre> element
+)
+
+// Limits on the size of the output a request may ask for, so that a single
+// request cannot make the server allocate unbounded memory
+const (
+	maxColumns = 1000 // output width in columns
+	maxRows    = 500  // canvas height, and rows of padding and margins
+)
+
+// defaultWidth is the output width of requests without a width or a canvas,
+// the width the command-line tool falls back to without a terminal
+const defaultWidth = 200
+
 type Response struct {
 	Result string `json:"result"`
 }
 
+type FontsResponse struct {
+	Fonts []Font `json:"fonts"`
+}
+
+type Font struct {
+	Name     string `json:"name"`
+	Embedded bool   `json:"embedded"`
+}
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
 func setupHandler() {
 	http.HandleFunc("/ascii-art", asciiArtHandler)
+	http.HandleFunc("/fonts", fontsHandler)
 	http.HandleFunc("/server.html", func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Serving server.html from: docs/server.html")
 		http.ServeFile(w, r, "docs/server.html")
@@ -37,6 +100,10 @@ func setupHandler() {
 }
 
 func main() {
+	reload := flag.Bool("reload", false, "reload banner files when they change on disk")
+	flag.Parse()
+
+	fonts = ascii.NewRegistry(ascii.DefaultResolver(), *reload)
 	setupHandler()
 	
 	log.Println("Server starting on :8080")
@@ -80,41 +147,185 @@ func asciiArtHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bannerFile := "../../assets/" + req.Banner + ".txt"
-	charMap, err := ascii.LoadBanner(bannerFile)
-	if err != nil {
-		// Try from project root (when running server)
-		bannerFile = "assets/" + req.Banner + ".txt"
-		charMap, err = ascii.LoadBanner(bannerFile)
+	if req.Layout != "" && !ascii.IsValidLayout(req.Layout) {
+		sendError(w, "Invalid layout", http.StatusBadRequest)
+		return
+	}
+
+	if req.Fallback != "" && !ascii.IsValidFallback(req.Fallback) {
+		sendError(w, "Invalid fallback", http.StatusBadRequest)
+		return
+	}
+
+	// Rows end with $ unless another output mode is requested
+	if req.Output == "" {
+		req.Output = ascii.OutputDollar
+	}
+	if !ascii.IsValidOutputMode(req.Output) {
+		sendError(w, "Invalid output mode", http.StatusBadRequest)
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = formatText
+	}
+	if req.Format != formatText && req.Format != formatHTML {
+		sendError(w, "Invalid format", http.StatusBadRequest)
+		return
+	}
+
+	width := 0
+	if req.Width != nil {
+		width = *req.Width
+	} else if req.Canvas == "" {
+		width = defaultWidth
+	}
+	if width > maxColumns {
+		sendError(w, fmt.Sprintf("Width exceeds %d columns", maxColumns), http.StatusBadRequest)
+		return
+	}
+
+	// The canvas size and spacings are given like the command-line flags
+	var canvas ascii.CanvasSize
+	if req.Canvas != "" {
+		parsed, err := ascii.ParseCanvasSize(req.Canvas)
+		if err != nil {
+			sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		canvas = parsed
+	}
+	var padding, margin ascii.Spacing
+	if req.Padding != "" {
+		parsed, err := ascii.ParseSpacing(req.Padding)
 		if err != nil {
-			sendError(w, "Banner not found", http.StatusNotFound)
+			sendError(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		padding = parsed
+	}
+	if req.Margin != "" {
+		parsed, err := ascii.ParseSpacing(req.Margin)
+		if err != nil {
+			sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		margin = parsed
+	}
+	if canvas.Width > maxColumns || canvas.Height > maxRows {
+		sendError(w, fmt.Sprintf("Canvas exceeds %dx%d", maxColumns, maxRows), http.StatusBadRequest)
+		return
+	}
+	if !withinLimit(maxColumns, padding.Left, padding.Right, margin.Left, margin.Right) || !withinLimit(maxRows, padding.Top, padding.Bottom, margin.Top, margin.Bottom) {
+		sendError(w, fmt.Sprintf("Padding and margins exceed %d columns or %d rows", maxColumns, maxRows), http.StatusBadRequest)
+		return
 	}
 
-	result := ascii.GenerateArtWithColorAndAlignment(req.Text, charMap, req.Substring, req.Color, req.Align)
-	
-	// Strip ANSI color codes for HTML display
-	result = stripANSI(result)
+	// Banners are selected by name only, never by path
+	font, err := fonts.FontByName(req.Banner)
+	if errors.Is(err, ascii.ErrBannerNotFound) {
+		sendError(w, "Banner not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		sendError(w, "Invalid banner", http.StatusInternalServerError)
+		return
+	}
+
+	rules := make([]ascii.ColorRule, len(req.Rules))
+	for i, rule := range req.Rules {
+		rules[i] = ascii.ColorRule{Pattern: rule.Pattern, Color: rule.Color}
+	}
+
+	// With the error fallback policy, text the font cannot render is rejected
+	renderer, err := ascii.NewRenderer(font, ascii.Options{
+		Color:     req.Color,
+		Substring: req.Substring,
+		Alignment: req.Align,
+		Width:     width,
+		Layout:    req.Layout,
+		Fallback:  req.Fallback,
+		Output:    req.Output,
+
+		Gradient:      req.Gradient,
+		GradientAngle: req.GradientAngle,
+		GradientSpace: req.GradientSpace,
+		Palette:       req.Palette,
+		Rules:         rules,
+		Background:    req.Bg,
+		Attributes:    req.Attr,
+		Match:         req.Match,
+		IgnoreCase:    req.IgnoreCase,
+		Wrap:          req.Wrap,
+		Hyphenate:     req.Hyphenate,
+		Canvas:        canvas,
+		VAlign:        req.VAlign,
+		Padding:       padding,
+		Margin:        margin,
+	})
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rendered, err := renderer.Render(req.Text)
+	if err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Plain text drops colors; HTML keeps them as inline styles
+	var encoder ascii.Encoder = ascii.PlainEncoder{}
+	if req.Format == formatHTML {
+		encoder = ascii.HTMLEncoder{}
+	}
+	result := rendered.Encode(encoder)
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(Response{Result: result})
 }
 
-func stripANSI(s string) string {
-	result := ""
-	inEscape := false
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
-			inEscape = true
-			i++
-		} else if inEscape && s[i] == 'm' {
-			inEscape = false
-		} else if !inEscape {
-			result += string(s[i])
+// withinLimit reports whether the sizes add up to no more than limit. Every
+// size is checked on its own first, so that huge values cannot overflow the sum.
+func withinLimit(limit int, sizes ...int) bool {
+	total := 0
+	for _, size := range sizes {
+		if size > limit {
+			return false
 		}
+		total += size
 	}
-	return result
+	return total <= limit
+}
+
+// fontsHandler lists the banners that can be selected by name
+func fontsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		sendError(w, "Method not allowed", http.StatusBadRequest)
+		return
+	}
+
+	available, err := fonts.List()
+	if err != nil {
+		sendError(w, "Failed to list fonts", http.StatusInternalServerError)
+		return
+	}
+
+	// File paths stay on the server; clients only need the names
+	response := FontsResponse{Fonts: []Font{}}
+	for _, font := range available {
+		response.Fonts = append(response.Fonts, Font{Name: font.Name, Embedded: font.Embedded})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
 func isValidAlignment(align string) bool {
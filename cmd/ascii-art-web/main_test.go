@@ -1,10 +1,13 @@
 package main
 
 import (
+	"ascii-art/internal/ascii"
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -194,3 +197,540 @@ func TestSendError(t *testing.T) {
 func TestSetupHandler(t *testing.T) {
 	setupHandler()
 }
+
+func TestAsciiArtHandler_WithLayout(t *testing.T) {
+	widths := make(map[string]int)
+	for _, layout := range []string{"full", "fit", "smush"} {
+		req := Request{Text: "Hi There", Banner: "standard", Layout: layout}
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 for layout=%s, got %d", layout, w.Code)
+		}
+		var resp Response
+		json.NewDecoder(w.Body).Decode(&resp)
+		widths[layout] = len(strings.Split(resp.Result, "\n")[0])
+	}
+
+	for _, layout := range []string{"fit", "smush"} {
+		if widths[layout] >= widths["full"] {
+			t.Errorf("layout=%s rows are %d columns, want fewer than the %d of full", layout, widths[layout], widths["full"])
+		}
+	}
+}
+
+func TestAsciiArtHandler_InvalidLayout(t *testing.T) {
+	req := Request{Text: "Hi", Banner: "standard", Layout: "invalid"}
+	body, _ := json.Marshal(req)
+	
+	r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	asciiArtHandler(w, r)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
+
+func TestAsciiArtHandler_Color(t *testing.T) {
+	tests := []struct {
+		color    string
+		wantCode int
+	}{
+		{"#ff8800", http.StatusOK},
+		{"rgb(255, 136, 0)", http.StatusOK},
+		{"hsl(32, 100%, 50%)", http.StatusOK},
+		{"ansi256:208", http.StatusOK},
+		{"rebeccapurple", http.StatusOK},
+		{"notacolor", http.StatusBadRequest},
+		{"#ff88", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := Request{Text: "Hi", Banner: "standard", Color: tt.color}
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("color %q: expected %d, got %d", tt.color, tt.wantCode, w.Code)
+		}
+		if tt.wantCode == http.StatusBadRequest && !strings.Contains(w.Body.String(), "invalid color") {
+			t.Errorf("color %q: error should name the invalid color, got %s", tt.color, w.Body.String())
+		}
+	}
+}
+
+func TestAsciiArtHandler_GradientHTML(t *testing.T) {
+	req := Request{Text: "Hi", Banner: "standard", Gradient: "#ff0000,#0000ff", GradientSpace: "perceptual", Format: "html"}
+	body, _ := json.Marshal(req)
+
+	r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	asciiArtHandler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp Response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	// The left edge is the first stop; further columns blend towards blue
+	if !strings.Contains(resp.Result, `<span style="color:#ff0000">`) {
+		t.Errorf("Expected the first stop in result, got: %s", resp.Result)
+	}
+	if !strings.Contains(resp.Result, `<span style="color:#3b3de1">`) {
+		t.Errorf("Expected a blended color in result, got: %s", resp.Result)
+	}
+	if strings.Contains(resp.Result, "\033[") {
+		t.Errorf("HTML result contains escape sequences: %q", resp.Result)
+	}
+}
+
+func TestAsciiArtHandler_Palette(t *testing.T) {
+	tests := []struct {
+		palette  string
+		wantCode int
+	}{
+		{"rainbow", http.StatusOK},
+		{"red,green,blue", http.StatusOK},
+		{"nope", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := Request{Text: "Hi", Banner: "standard", Palette: tt.palette, Format: "html"}
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("palette %q: expected %d, got %d", tt.palette, tt.wantCode, w.Code)
+		}
+		if tt.wantCode == http.StatusOK && !strings.Contains(w.Body.String(), "span") {
+			t.Errorf("palette %q: expected colored spans, got %s", tt.palette, w.Body.String())
+		}
+	}
+}
+
+func TestAsciiArtHandler_Rules(t *testing.T) {
+	tests := []struct {
+		rules    []Rule
+		wantCode int
+		want     []string
+	}{
+		{[]Rule{{Pattern: "ERR", Color: "red"}, {Pattern: "/O+K/", Color: "green"}}, http.StatusOK, []string{"color:#cd0000", "color:#00cd00"}},
+		{[]Rule{{Pattern: "ERR", Color: "nope"}}, http.StatusBadRequest, nil},
+		{[]Rule{{Pattern: "/[/", Color: "red"}}, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		req := Request{Text: "ERR OK", Banner: "standard", Rules: tt.rules, Format: "html"}
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("rules %+v: expected %d, got %d", tt.rules, tt.wantCode, w.Code)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(w.Body.String(), want) {
+				t.Errorf("rules %+v: expected %s in result, got %s", tt.rules, want, w.Body.String())
+			}
+		}
+	}
+}
+
+func TestAsciiArtHandler_Match(t *testing.T) {
+	tests := []struct {
+		req      Request
+		wantCode int
+	}{
+		{Request{Text: "Error 42", Color: "red", Substring: "error|[0-9]+", Match: "regex", IgnoreCase: true}, http.StatusOK},
+		{Request{Text: "Error 42", Color: "red", Substring: "error", IgnoreCase: true}, http.StatusOK},
+		{Request{Text: "Error 42", Color: "red", Substring: "(", Match: "regex"}, http.StatusBadRequest},
+		{Request{Text: "Error 42", Color: "red", Substring: "e", Match: "glob"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt.req.Banner = "standard"
+		tt.req.Format = "html"
+		body, _ := json.Marshal(tt.req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%+v: expected %d, got %d", tt.req, tt.wantCode, w.Code)
+		}
+		if tt.wantCode == http.StatusOK && !strings.Contains(w.Body.String(), "color:#cd0000") {
+			t.Errorf("%+v: expected the match to be colored, got %s", tt.req, w.Body.String())
+		}
+	}
+}
+
+func TestAsciiArtHandler_BackgroundAndAttributes(t *testing.T) {
+	tests := []struct {
+		req      Request
+		wantCode int
+		want     string
+	}{
+		{Request{Text: "Hi", Color: "white", Bg: "blue", Attr: "bold,underline"}, http.StatusOK, "background-color:#0000ee;font-weight:bold;text-decoration:underline"},
+		{Request{Text: "Hi", Bg: "#112233", Substring: "i"}, http.StatusOK, "background-color:#112233"},
+		{Request{Text: "Hi", Bg: "nope"}, http.StatusBadRequest, ""},
+		{Request{Text: "Hi", Attr: "strike"}, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		tt.req.Banner = "standard"
+		tt.req.Format = "html"
+		body, _ := json.Marshal(tt.req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%+v: expected %d, got %d", tt.req, tt.wantCode, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), tt.want) {
+			t.Errorf("%+v: expected %s in result, got %s", tt.req, tt.want, w.Body.String())
+		}
+	}
+}
+
+func TestAsciiArtHandler_Wrap(t *testing.T) {
+	tests := []struct {
+		req       Request
+		wantCode  int
+		wantWords []int // words on each row of glyphs
+	}{
+		{Request{Wrap: "balanced"}, http.StatusOK, []int{2, 2}},
+		{Request{Wrap: "greedy", Hyphenate: true}, http.StatusOK, []int{3, 1}},
+		{Request{Wrap: "none"}, http.StatusOK, []int{4}},
+		{Request{Wrap: "even"}, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		tt.req.Text, tt.req.Banner, tt.req.Width = "Hi Hi Hi Hi", "standard", columns(60)
+		body, _ := json.Marshal(tt.req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("wrap=%s: expected %d, got %d", tt.req.Wrap, tt.wantCode, w.Code)
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+
+		var resp Response
+		json.NewDecoder(w.Body).Decode(&resp)
+		rows := strings.Split(resp.Result, "\n")
+		var words []int
+		for i := 1; i < len(rows); i += 8 {
+			// The dot of each i sits on the second row of its glyph
+			words = append(words, strings.Count(rows[i], "(_)"))
+		}
+		if fmt.Sprint(words) != fmt.Sprint(tt.wantWords) {
+			t.Errorf("wrap=%s: words per row = %v, want %v", tt.req.Wrap, words, tt.wantWords)
+		}
+	}
+}
+
+// columns returns a pointer to a width, for the optional width field
+func columns(n int) *int {
+	return &n
+}
+
+func TestAsciiArtHandler_Width(t *testing.T) {
+	rows := func(req Request) (int, []string) {
+		req.Banner = "standard"
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		var resp Response
+		json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, strings.Split(resp.Result, "\n")
+	}
+
+	if code, got := rows(Request{Text: "Hello There World"}); code != http.StatusOK || len(got) != 8 {
+		t.Errorf("without a width: expected 8 rows, got %d (status %d)", len(got), code)
+	}
+	long := "Hello There World Hello There World"
+	if code, got := rows(Request{Text: long}); code != http.StatusOK || len(got) <= 8 {
+		t.Errorf("without a width: expected rows wrapped to %d columns, got %d (status %d)", defaultWidth, len(got), code)
+	}
+	if code, got := rows(Request{Text: long, Width: columns(0)}); code != http.StatusOK || len(got) != 8 {
+		t.Errorf("width 0: expected 8 unwrapped rows, got %d (status %d)", len(got), code)
+	}
+
+	// Without a width, rows are aligned to the default width
+	_, right := rows(Request{Text: "Hi", Align: "right"})
+	_, center := rows(Request{Text: "Hi", Align: "center"})
+	_, left := rows(Request{Text: "Hi"})
+	for i := range left {
+		content := strings.TrimSuffix(left[i], "$")
+		if want := strings.Repeat(" ", defaultWidth-1-len(content)) + content + "$"; right[i] != want {
+			t.Errorf("align right: row %d = %q, want %q", i, right[i], want)
+		}
+		if want := strings.Repeat(" ", (defaultWidth-1-len(content))/2) + content + "$"; center[i] != want {
+			t.Errorf("align center: row %d = %q, want %q", i, center[i], want)
+		}
+	}
+	code, wrapped := rows(Request{Text: "Hello There World", Width: columns(60)})
+	if code != http.StatusOK || len(wrapped) <= 8 {
+		t.Errorf("width 60: expected wrapped rows, got %d (status %d)", len(wrapped), code)
+	}
+	for _, row := range wrapped {
+		if len(row) > 60 {
+			t.Errorf("width 60: row %q is too wide", row)
+		}
+	}
+	if code, got := rows(Request{Text: "Supercalifragilistic", Width: columns(60), Hyphenate: true}); code != http.StatusOK || !strings.HasSuffix(strings.TrimSuffix(got[3], "$"), "|______| ") {
+		t.Errorf("hyphenate: expected the first row to end with a hyphen, got %q (status %d)", got[3], code)
+	}
+	if code, _ := rows(Request{Text: "Hi", Width: columns(-1)}); code != http.StatusBadRequest {
+		t.Errorf("negative width: expected %d, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := rows(Request{Text: "Hi", Width: columns(1000000000), Align: "right"}); code != http.StatusBadRequest {
+		t.Errorf("huge width: expected %d, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := rows(Request{Text: "Hi", Width: columns(maxColumns), Align: "right"}); code != http.StatusOK {
+		t.Errorf("width %d: expected %d, got %d", maxColumns, http.StatusOK, code)
+	}
+}
+
+func TestAsciiArtHandler_Canvas(t *testing.T) {
+	rows := func(req Request) (int, []string) {
+		req.Text, req.Banner = "Hi", "standard"
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		var resp Response
+		json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, strings.Split(resp.Result, "\n")
+	}
+
+	code, got := rows(Request{Canvas: "50x12", VAlign: "bottom", Padding: "1", Margin: "0,2", Output: "none"})
+	if code != http.StatusOK || len(got) != 12 {
+		t.Fatalf("canvas 50x12: expected 12 rows, got %d (status %d)", len(got), code)
+	}
+	for _, row := range got {
+		if len(row) != 50 {
+			t.Errorf("canvas 50x12: row %q is not 50 columns wide", row)
+		}
+	}
+	if strings.TrimSpace(got[0]) != "" || strings.TrimSpace(got[3]) == "" {
+		t.Errorf("valign bottom: expected blank rows above the text, got %q", got)
+	}
+
+	if code, got := rows(Request{Canvas: fmt.Sprintf("%dx%d", maxColumns, maxRows)}); code != http.StatusOK || len(got) != maxRows {
+		t.Errorf("largest canvas: expected %d rows, got %d (status %d)", maxRows, len(got), code)
+	}
+
+	for _, req := range []Request{
+		{Canvas: "50"},
+		{Canvas: "50x12", Width: columns(40)},
+		{Canvas: "50x12", VAlign: "center"},
+		{Canvas: "30x10", Output: "trim"},
+		{Padding: "1,2,3"},
+		{Margin: "-1"},
+		{Canvas: "100000x100000"},
+		{Canvas: fmt.Sprintf("%dx10", maxColumns+1)},
+		{Padding: "100000000"},
+		{Padding: "0,400", Margin: "0,101"},
+		{Padding: "0,4611686018427387904"},
+		{Margin: "4611686018427387904,0"},
+	} {
+		if code, _ := rows(req); code != http.StatusBadRequest {
+			t.Errorf("%+v: expected %d, got %d", req, http.StatusBadRequest, code)
+		}
+	}
+}
+
+func TestAsciiArtHandler_InvalidFormat(t *testing.T) {
+	for _, req := range []Request{
+		{Text: "Hi", Banner: "standard", Format: "ansi"},
+		{Text: "Hi", Banner: "standard", Gradient: "#ff0000"},
+		{Text: "Hi", Banner: "standard", Gradient: "#ff0000,#0000ff", Color: "red"},
+	} {
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%+v: expected 400, got %d", req, w.Code)
+		}
+	}
+}
+
+func TestAsciiArtHandler_FallbackError(t *testing.T) {
+	req := Request{Text: "café", Banner: "standard", Fallback: "error"}
+	body, _ := json.Marshal(req)
+	
+	r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	asciiArtHandler(w, r)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
+
+func TestAsciiArtHandler_FallbackTransliterate(t *testing.T) {
+	render := func(req Request) string {
+		req.Banner = "standard"
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 for %q, got %d", req.Text, w.Code)
+		}
+		var resp Response
+		json.NewDecoder(w.Body).Decode(&resp)
+		return resp.Result
+	}
+
+	got := render(Request{Text: "café", Fallback: "transliterate"})
+	if want := render(Request{Text: "cafe"}); got != want {
+		t.Errorf("café rendered as\n%s\nwant the rendering of cafe\n%s", got, want)
+	}
+}
+
+func TestAsciiArtHandler_OutputMode(t *testing.T) {
+	tests := []struct {
+		output     string
+		wantStatus int
+		wantDollar bool
+	}{
+		{"", http.StatusOK, true},
+		{"dollar", http.StatusOK, true},
+		{"none", http.StatusOK, false},
+		{"trim", http.StatusOK, false},
+		{"crlf", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		req := Request{Text: "Hi", Banner: "standard", Output: tt.output}
+		body, _ := json.Marshal(req)
+
+		r := httptest.NewRequest(http.MethodPost, "/ascii-art", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		asciiArtHandler(w, r)
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("Expected %d for output=%q, got %d", tt.wantStatus, tt.output, w.Code)
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+
+		var resp Response
+		json.NewDecoder(w.Body).Decode(&resp)
+		if strings.Contains(resp.Result, "$") != tt.wantDollar {
+			t.Errorf("output=%q result contains $ = %v, want %v", tt.output, !tt.wantDollar, tt.wantDollar)
+		}
+		if tt.output == "trim" && strings.Contains(resp.Result, " \n") {
+			t.Errorf("output=trim result keeps trailing spaces: %q", resp.Result)
+		}
+	}
+}
+
+func TestFontsHandler(t *testing.T) {
+	// List only the embedded banners, whatever fonts the user has installed
+	saved := fonts
+	fonts = ascii.NewRegistry(ascii.NewResolver(), false)
+	t.Cleanup(func() { fonts = saved })
+
+	r := httptest.NewRequest(http.MethodGet, "/fonts", nil)
+	w := httptest.NewRecorder()
+
+	fontsHandler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+
+	var resp FontsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, font := range resp.Fonts {
+		names[font.Name] = true
+	}
+	for _, name := range []string{"standard", "shadow", "thinkertoy"} {
+		if !names[name] {
+			t.Errorf("Expected %s in font list, got %+v", name, resp.Fonts)
+		}
+	}
+}
+
+func TestFontsHandler_MethodNotAllowed(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/fonts", nil)
+	w := httptest.NewRecorder()
+
+	fontsHandler(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
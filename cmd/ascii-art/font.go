@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"ascii-art/internal/ascii"
+)
+
+// fontCommands lists the subcommands of "ascii-art font"
+var fontCommands = map[string]func(args []string) int{
+	"list": listFonts,
+	"lint": lintFont,
+}
+
+// isFontCommand reports whether args invoke a "font" subcommand rather than
+// render the word "font"
+func isFontCommand(args []string) bool {
+	if len(args) < 2 || args[0] != "font" {
+		return false
+	}
+	_, exists := fontCommands[args[1]]
+	return exists
+}
+
+// runFontCommand runs a "font" subcommand and returns the exit code
+func runFontCommand(args []string) int {
+	return fontCommands[args[0]](args[1:])
+}
+
+// listFonts prints the banners available by name, with the file each one is
+// loaded from
+func listFonts(args []string) int {
+	if len(args) > 0 {
+		printUsage()
+		return 1
+	}
+
+	fonts, err := ascii.DefaultResolver().List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing fonts: %v\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, font := range fonts {
+		source := font.Path
+		if font.Embedded {
+			source = "(built in)"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", font.Name, source)
+	}
+	w.Flush()
+	return 0
+}
+
+// lintFont checks a banner file and prints one line per problem found. The
+// exit code is 1 when the file has errors, so the command can gate CI.
+func lintFont(args []string) int {
+	if len(args) != 1 {
+		printUsage()
+		return 1
+	}
+	filename := args[0]
+
+	diagnostics, err := ascii.LintBanner(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, d := range diagnostics {
+		if d.Line == 0 {
+			fmt.Printf("%s: %s\n", filename, d)
+		} else {
+			fmt.Printf("%s:%s\n", filename, d)
+		}
+	}
+	if len(diagnostics) == 0 {
+		fmt.Printf("%s: no problems found\n", filename)
+	}
+
+	if ascii.HasErrors(diagnostics) {
+		return 1
+	}
+	return 0
+}
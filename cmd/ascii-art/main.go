@@ -1,29 +1,54 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"ascii-art/internal/ascii"
 )
 
 func main() {
-	var colorFlag, substring, text, outputFile, banner, alignFlag string
+	var colorFlag, substring, text, outputFile, banner, alignFlag, layoutFlag, fallbackFlag string
 	banner = "standard" // default banner
+	outputMode := ascii.OutputDollar // rows end with $ unless another mode is requested
+	colorMode := ascii.ColorModeAuto // colors only when stdout is a color terminal
+	var gradientFlag, gradientSpace, paletteFlag string
+	var rules []ascii.ColorRule // --color=color:pattern flags in command-line order
+	var matchFlag, bgFlag, attrFlag, wrapFlag string
+	ignoreCase, hyphenate := false, false
+	var gradientAngle float64
+	width := -1 // output width; detected from the terminal unless --width is given
+	var canvas ascii.CanvasSize
+	var valignFlag string
+	var padding, margin ascii.Spacing
 	hasColorFlag := false
 
-	// Parse arguments - extract flags first
+	// Parse arguments - "font" subcommands first, then flags
 	args := os.Args[1:]
+	if isFontCommand(args) {
+		os.Exit(runFontCommand(args[1:]))
+	}
 	for i := len(args) - 1; i >= 0; i-- {
 		arg := args[i]
 		// Parse --output=filename flag
 		if strings.HasPrefix(arg, "--output=") {
 			outputFile = strings.TrimPrefix(arg, "--output=")
 			args = append(args[:i], args[i+1:]...)
-		// Parse --color=color flag
+		// Parse --color=color and repeatable --color=color:pattern flags
 		} else if strings.HasPrefix(arg, "--color=") {
-			colorFlag = strings.TrimPrefix(arg, "--color=")
+			value := strings.TrimPrefix(arg, "--color=")
+			if _, err := ascii.ParseColor(value); err != nil {
+				if rule, err := ascii.ParseColorRule(value); err == nil {
+					// Arguments are scanned backwards, so prepend to keep the rules in order
+					rules = append([]ascii.ColorRule{rule}, rules...)
+					args = append(args[:i], args[i+1:]...)
+					continue
+				}
+			}
+			colorFlag = value
 			hasColorFlag = true
 			args = append(args[:i], args[i+1:]...)
 		// Parse --align=type flag
@@ -35,6 +60,143 @@ func main() {
 				return
 			}
 			args = append(args[:i], args[i+1:]...)
+		// Parse --layout=mode flag
+		} else if strings.HasPrefix(arg, "--layout=") {
+			layoutFlag = strings.TrimPrefix(arg, "--layout=")
+			if !ascii.IsValidLayout(layoutFlag) {
+				printUsage()
+				return
+			}
+			args = append(args[:i], args[i+1:]...)
+		// Parse --fallback=policy flag
+		} else if strings.HasPrefix(arg, "--fallback=") {
+			fallbackFlag = strings.TrimPrefix(arg, "--fallback=")
+			if !ascii.IsValidFallback(fallbackFlag) {
+				printUsage()
+				return
+			}
+			args = append(args[:i], args[i+1:]...)
+		// Parse --output-mode=mode flag
+		} else if strings.HasPrefix(arg, "--output-mode=") {
+			outputMode = strings.TrimPrefix(arg, "--output-mode=")
+			if !ascii.IsValidOutputMode(outputMode) {
+				printUsage()
+				return
+			}
+			args = append(args[:i], args[i+1:]...)
+		// Parse --gradient=stops flag
+		} else if strings.HasPrefix(arg, "--gradient=") {
+			gradientFlag = strings.TrimPrefix(arg, "--gradient=")
+			hasColorFlag = true
+			args = append(args[:i], args[i+1:]...)
+		// Parse --palette=colors flag
+		} else if strings.HasPrefix(arg, "--palette=") {
+			paletteFlag = strings.TrimPrefix(arg, "--palette=")
+			hasColorFlag = true
+			args = append(args[:i], args[i+1:]...)
+		// Parse --bg=color flag
+		} else if strings.HasPrefix(arg, "--bg=") {
+			bgFlag = strings.TrimPrefix(arg, "--bg=")
+			hasColorFlag = true
+			args = append(args[:i], args[i+1:]...)
+		// Parse --attr=attributes flag
+		} else if strings.HasPrefix(arg, "--attr=") {
+			attrFlag = strings.TrimPrefix(arg, "--attr=")
+			if _, err := ascii.ParseAttrs(attrFlag); err != nil {
+				printUsage()
+				return
+			}
+			hasColorFlag = true
+			args = append(args[:i], args[i+1:]...)
+		// Parse --gradient-angle=degrees flag
+		} else if strings.HasPrefix(arg, "--gradient-angle=") {
+			angle, err := strconv.ParseFloat(strings.TrimPrefix(arg, "--gradient-angle="), 64)
+			if err != nil {
+				printUsage()
+				return
+			}
+			gradientAngle = angle
+			args = append(args[:i], args[i+1:]...)
+		// Parse --gradient-space=space flag
+		} else if strings.HasPrefix(arg, "--gradient-space=") {
+			gradientSpace = strings.TrimPrefix(arg, "--gradient-space=")
+			if !ascii.IsValidInterpolation(gradientSpace) {
+				printUsage()
+				return
+			}
+			args = append(args[:i], args[i+1:]...)
+		// Parse --match=mode flag
+		} else if strings.HasPrefix(arg, "--match=") {
+			matchFlag = strings.TrimPrefix(arg, "--match=")
+			if !ascii.IsValidMatchMode(matchFlag) {
+				printUsage()
+				return
+			}
+			args = append(args[:i], args[i+1:]...)
+		// Parse --ignore-case flag
+		} else if arg == "--ignore-case" {
+			ignoreCase = true
+			args = append(args[:i], args[i+1:]...)
+		// Parse --width=columns flag
+		} else if strings.HasPrefix(arg, "--width=") {
+			columns, err := strconv.Atoi(strings.TrimPrefix(arg, "--width="))
+			if err != nil || columns < 0 {
+				printUsage()
+				return
+			}
+			width = columns
+			args = append(args[:i], args[i+1:]...)
+		// Parse --canvas=columnsxrows flag
+		} else if strings.HasPrefix(arg, "--canvas=") {
+			size, err := ascii.ParseCanvasSize(strings.TrimPrefix(arg, "--canvas="))
+			if err != nil {
+				printUsage()
+				return
+			}
+			canvas = size
+			args = append(args[:i], args[i+1:]...)
+		// Parse --valign=position flag
+		} else if strings.HasPrefix(arg, "--valign=") {
+			valignFlag = strings.TrimPrefix(arg, "--valign=")
+			if !ascii.IsValidVAlign(valignFlag) {
+				printUsage()
+				return
+			}
+			args = append(args[:i], args[i+1:]...)
+		// Parse --padding=spacing and --margin=spacing flags
+		} else if strings.HasPrefix(arg, "--padding=") || strings.HasPrefix(arg, "--margin=") {
+			name, value, _ := strings.Cut(arg, "=")
+			spacing, err := ascii.ParseSpacing(value)
+			if err != nil {
+				printUsage()
+				return
+			}
+			if name == "--padding" {
+				padding = spacing
+			} else {
+				margin = spacing
+			}
+			args = append(args[:i], args[i+1:]...)
+		// Parse --wrap=mode flag
+		} else if strings.HasPrefix(arg, "--wrap=") {
+			wrapFlag = strings.TrimPrefix(arg, "--wrap=")
+			if !ascii.IsValidWrap(wrapFlag) {
+				printUsage()
+				return
+			}
+			args = append(args[:i], args[i+1:]...)
+		// Parse --hyphenate flag
+		} else if arg == "--hyphenate" {
+			hyphenate = true
+			args = append(args[:i], args[i+1:]...)
+		// Parse --color-mode=mode flag
+		} else if strings.HasPrefix(arg, "--color-mode=") {
+			colorMode = strings.TrimPrefix(arg, "--color-mode=")
+			if !ascii.IsValidColorMode(colorMode) {
+				printUsage()
+				return
+			}
+			args = append(args[:i], args[i+1:]...)
 		}
 	}
 
@@ -76,16 +238,74 @@ func main() {
 		return
 	}
 
-	// Load the specified banner
-	bannerFile := "assets/" + banner + ".txt"
-	charMap, err := ascii.LoadBanner(bannerFile)
+	// Load the specified banner: an explicit path, or a name looked up in the
+	// user font directories and then among the banners built into the binary
+	font, err := ascii.NewRegistry(ascii.DefaultResolver(), false).Font(banner)
+	if errors.Is(err, ascii.ErrBannerNotFound) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printUsage()
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading banner: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Generate ASCII art with color and alignment support
-	result := ascii.GenerateArtWithColorAndAlignment(text, charMap, substring, colorFlag, alignFlag)
+	// Without --width, rows wrap and align to the terminal, or to the canvas
+	// with --canvas; --width=0 turns wrapping off
+	if width < 0 {
+		width = 0
+		if canvas.IsZero() {
+			width = ascii.TerminalWidth()
+		}
+	}
+
+	// The --layout flag overrides the layout declared by the banner, and
+	// characters without a glyph are skipped unless another policy is requested
+	renderer, err := ascii.NewRenderer(font, ascii.Options{
+		Color:     colorFlag,
+		Substring: substring,
+		Alignment: alignFlag,
+		Width:     width,
+		Layout:    layoutFlag,
+		Fallback:  fallbackFlag,
+		Output:    outputMode,
+
+		Gradient:      gradientFlag,
+		GradientAngle: gradientAngle,
+		GradientSpace: gradientSpace,
+		Palette:       paletteFlag,
+		Rules:         rules,
+		Background:    bgFlag,
+		Attributes:    attrFlag,
+		Match:         matchFlag,
+		IgnoreCase:    ignoreCase,
+		Wrap:          wrapFlag,
+		Hyphenate:     hyphenate,
+		Canvas:        canvas,
+		VAlign:        valignFlag,
+		Padding:       padding,
+		Margin:        margin,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Generate ASCII art with color, alignment and layout support
+	rendered, err := renderer.Render(text)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Colors are downsampled to what stdout supports; files are not terminals,
+	// so they only receive colors with --color-mode=always
+	out := os.Stdout
+	if outputFile != "" {
+		out = nil
+	}
+	result := rendered.Encode(ascii.ANSIEncoder{Profile: ascii.DetectColorProfile(colorMode, out)})
 	if result != "" {
 		// Save to file or print to stdout
 		if outputFile != "" {
@@ -113,4 +333,18 @@ func isValidAlignment(align string) bool {
 func printUsage() {
 	fmt.Println("Usage: go run . [OPTION] [STRING] [BANNER]")
 	fmt.Println("\nExample: go run . --align=right something standard")
+	fmt.Println("         go run . --layout=smush something big.flf")
+	fmt.Println("         go run . --fallback=transliterate café standard")
+	fmt.Println("         go run . --output-mode=trim something standard")
+	fmt.Println("         go run . --color=#ff8800 --color-mode=always something standard")
+	fmt.Println("         go run . --gradient=#ff0000,#0000ff --gradient-angle=45 something standard")
+	fmt.Println("         go run . --palette=rainbow something standard")
+	fmt.Println("         go run . --color=white --bg=blue --attr=bold,underline Hello \"Hello there\" standard")
+	fmt.Println("         go run . --color=red:ERROR --color=green:OK \"ERROR OK\" standard")
+	fmt.Println("         go run . --color=red --match=regex --ignore-case \"error|[0-9]+\" \"Error 42\" standard")
+	fmt.Println("         go run . --width=80 --align=center something standard")
+	fmt.Println("         go run . --wrap=greedy --hyphenate Supercalifragilistic standard")
+	fmt.Println("         go run . --canvas=80x24 --valign=middle --align=center --padding=1,2 --margin=1 something standard")
+	fmt.Println("         go run . font list")
+	fmt.Println("         go run . font lint myfont.txt")
 }
\ No newline at end of file
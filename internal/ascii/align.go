@@ -0,0 +1,99 @@
+package ascii
+
+// alignmentPadding returns how many columns a row of the given width is
+// shifted to the right, leaving room for the $ terminator
+func alignmentPadding(alignment string, width, termWidth int) int {
+	padding := 0
+	switch alignment {
+	case "right":
+		padding = termWidth - width - 1
+	case "center":
+		if width < termWidth {
+			padding = (termWidth - width - 1) / 2
+		}
+	}
+	if padding < 0 {
+		return 0
+	}
+	return padding
+}
+
+// alignRows pads every non-empty row for the alignment. Justified lines are
+// laid out by justify instead and are left as they are.
+func alignRows(rows [][]Cell, alignment string, termWidth int) [][]Cell {
+	for i, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		if padding := alignmentPadding(alignment, len(row), termWidth); padding > 0 {
+			rows[i] = append(blankCells(padding), row...)
+		}
+	}
+	return rows
+}
+
+// justify lays out a line like justified type: the line wraps as the wrap
+// mode decides, and every row but the last is stretched to the terminal width
+// by widening the gaps between its words. A line that fits on a single row is
+// stretched as well. The gaps take the style of the spaces they stand for, so
+// highlighted ranges spanning several words stay continuous.
+func (r *Renderer) justify(chars []rune, offset int, styles []Style, termWidth int) [][]Cell {
+	maxWidth := termWidth - 1 // reserve room for the $ terminator
+
+	segments := r.segments(chars, termWidth)
+	var rows [][]Cell
+	for i, seg := range segments {
+		words := wordSpans(chars, seg.span)
+		if len(words) < 2 || (i == len(segments)-1 && len(segments) > 1) {
+			// Single words and the last row of a paragraph stay left-aligned
+			rows = append(rows, r.cells(chars, seg, offset, styles)...)
+			continue
+		}
+		rows = append(rows, r.stretch(chars, seg, words, offset, styles, maxWidth)...)
+	}
+	return rows
+}
+
+// stretch renders the words of a segment spread across maxWidth columns,
+// with at least one column between words
+func (r *Renderer) stretch(chars []rune, seg segment, words []span, offset int, styles []Style, maxWidth int) [][]Cell {
+	// Render every word on its own and measure it; a hyphen ending the
+	// segment belongs to its last word
+	blocks := make([][][]Cell, len(words))
+	totalWidth := 0
+	for i, word := range words {
+		blocks[i] = r.cells(chars, segment{span: word, hyphen: seg.hyphen && i == len(words)-1}, offset, styles)
+		totalWidth += len(blocks[i][0])
+	}
+
+	// Spread the remaining space over the gaps, at least one column each
+	gaps := len(words) - 1
+	spacePerGap, extraSpaces := 1, 0
+	if spacing := maxWidth - totalWidth; spacing > gaps {
+		spacePerGap = spacing / gaps
+		extraSpaces = spacing % gaps
+	}
+
+	rows := make([][]Cell, r.font.Height)
+	for row := range rows {
+		var cells []Cell
+		for i, word := range words {
+			cells = append(cells, blocks[i][row]...)
+			if i == gaps {
+				break
+			}
+			spaces := spacePerGap
+			if i < extraSpaces {
+				spaces++
+			}
+			gap := blankCells(spaces)
+			for k := range gap {
+				gap[k].Style = styles[word.end]
+				gap[k].Source = offset + word.end
+			}
+			cells = append(cells, gap...)
+		}
+		rows[row] = cells
+	}
+	return rows
+}
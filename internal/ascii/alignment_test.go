@@ -180,48 +180,49 @@ func TestAlignCenter(t *testing.T) {
 func TestAlignJustify(t *testing.T) {
 	tests := []struct {
 		name      string
-		lines     []string
+		text      string
 		termWidth int
+		want      []string
 	}{
 		{
 			name:      "Simple justify alignment",
-			lines:     []string{"hello$"},
+			text:      "ab cd",
 			termWidth: 20,
+			want:      []string{"ab" + strings.Repeat(" ", 15) + "cd$"},
 		},
 		{
 			name:      "Multiple lines justify",
-			lines:     []string{"hello$", "world$"},
-			termWidth: 30,
+			text:      `ab cd ef\nab cd`,
+			termWidth: 12,
+			want:      []string{"ab   cd  ef$", "ab       cd$"},
 		},
 		{
 			name:      "Empty line handling",
-			lines:     []string{"hello$", "$", "world$"},
-			termWidth: 25,
+			text:      `ab cd\n\nab cd`,
+			termWidth: 10,
+			want:      []string{"ab     cd$", "$", "ab     cd$"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := alignJustifyConsistent(tt.lines, tt.termWidth, "")
-			
-			// Check that we get the same number of lines
-			if len(result) != len(tt.lines) {
-				t.Errorf("alignJustify() returned %d lines, want %d", len(result), len(tt.lines))
+			renderer, err := NewRenderer(narrowFont("abcdef "), Options{Width: tt.termWidth, Alignment: "justify", Output: OutputDollar})
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
 			}
-			
-			// Check that all non-empty lines end with $
-			for i, line := range result {
-				if line != "" && line != "$" && !strings.HasSuffix(line, "$") {
-					t.Errorf("Line %d does not end with $: %q", i, line)
-				}
+			result, err := renderer.Render(tt.text)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
 			}
-			
-			// Check that content is distributed (not same as original for non-empty lines)
-			for i := range result {
-				if tt.lines[i] != "$" && tt.lines[i] != "" {
-					// For justify with empty originalText, it should be left-aligned (no change)
-					// So we just check it doesn't crash and returns same number of lines
-				}
+
+			lines := strings.Split(result.Encode(PlainEncoder{}), "\n")
+			if strings.Join(lines, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Render(%q) = %q, want %q", tt.text, lines, tt.want)
+			}
+
+			// Rendered lines no longer know their words, so justifying them is an error
+			if _, err := ApplyAlignmentWidth(lines, "justify", tt.termWidth); err == nil {
+				t.Error("ApplyAlignmentWidth(justify) error = nil, want an error")
 			}
 		})
 	}
@@ -318,4 +319,117 @@ func TestAlignmentEdgeCases(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestGetVisualLengthMultiByte(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"abc", 3},
+		{"█▀▄", 3},
+		{"\033[31mé_é\033[0m", 3},
+		{"\033[38;2;255;0;0m░\033[0m░", 2},
+	}
+
+	for _, tt := range tests {
+		if got := getVisualLength(tt.s); got != tt.want {
+			t.Errorf("getVisualLength(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestApplyAlignmentWidth(t *testing.T) {
+	lines := []string{"abc$", "$", "\033[31mab\033[0m$"}
+
+	tests := []struct {
+		alignment string
+		width     int
+		want      []string
+	}{
+		{"right", 20, []string{strings.Repeat(" ", 16) + "abc$", "$", strings.Repeat(" ", 17) + "\033[31mab\033[0m$"}},
+		{"center", 20, []string{strings.Repeat(" ", 8) + "abc$", "$", strings.Repeat(" ", 8) + "\033[31mab\033[0m$"}},
+		{"left", 20, lines},
+		{"right", 5, lines},
+	}
+
+	for _, tt := range tests {
+		got, err := ApplyAlignmentWidth(append([]string(nil), lines...), tt.alignment, tt.width)
+		if err != nil {
+			t.Fatalf("ApplyAlignmentWidth(%q, %d) error = %v", tt.alignment, tt.width, err)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("ApplyAlignmentWidth(%q, %d) = %q, want %q", tt.alignment, tt.width, got, tt.want)
+		}
+	}
+
+	if _, err := ApplyAlignmentWidth(lines, "middle", 20); err == nil {
+		t.Error("ApplyAlignmentWidth(middle) error = nil, want an error")
+	}
+}
+
+func TestRendererJustify(t *testing.T) {
+	font := narrowFont("abcdefgh -")
+
+	render := func(text string, options Options) *Result {
+		t.Helper()
+		options.Alignment, options.Output = "justify", OutputDollar
+		renderer, err := NewRenderer(font, options)
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render(text)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		return result
+	}
+
+	t.Run("stretches every row but the last", func(t *testing.T) {
+		result := render("aa bb cc dd ee ff gg", Options{Width: 16, Wrap: WrapGreedy})
+		want := []string{"aa  bb cc dd ee", "ff gg"}
+		for i, row := range want {
+			if got := result.Canvas.Text(i); got != row {
+				t.Errorf("row %d = %q, want %q", i, got, row)
+			}
+		}
+	})
+
+	t.Run("stretches a single row", func(t *testing.T) {
+		result := render("aa bb cc", Options{Width: 16})
+		if got, want := result.Canvas.Text(0), "aa     bb    cc"; got != want {
+			t.Errorf("row = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("justifies within the widest line without a width", func(t *testing.T) {
+		result := render(`aaaa bbbb cccc\na b c\nabc`, Options{})
+		want := []string{"aaaa bbbb cccc", "a      b     c", "abc"}
+		for i, row := range want {
+			if got := result.Canvas.Text(i); got != row {
+				t.Errorf("row %d = %q, want %q", i, got, row)
+			}
+		}
+	})
+
+	t.Run("gaps keep the style of the spaces", func(t *testing.T) {
+		result := render("aa bb cc", Options{Width: 16, Background: "blue", Substring: "a bb c"})
+		blue := Color{Type: ColorBasic, Index: 4}
+		for x, cell := range result.Canvas.Rows[0] {
+			highlighted := x >= 1 && x <= 13
+			if got := cell.Style.Background == blue; got != highlighted {
+				t.Errorf("column %d (%q) highlighted = %v, want %v", x, cell.Rune, got, highlighted)
+			}
+		}
+	})
+
+	t.Run("keeps hyphens of split words", func(t *testing.T) {
+		result := render("ab cdefghabcdefgh", Options{Width: 12, Wrap: WrapGreedy, Hyphenate: true})
+		want := []string{"ab", "cdefghabc-", "defgh"}
+		for i, row := range want {
+			if got := result.Canvas.Text(i); got != row {
+				t.Errorf("row %d = %q, want %q", i, got, row)
+			}
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package ascii
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -156,6 +157,38 @@ func TestGenerateArt(t *testing.T) {
 	}
 }
 
+// renderArt renders text with RenderArt, failing the test on error
+func renderArt(t *testing.T, text string, font *Font, substring, color, alignment string) string {
+	t.Helper()
+	art, err := RenderArt(text, font, substring, color, alignment)
+	if err != nil {
+		t.Fatalf("RenderArt() error = %v", err)
+	}
+	return art
+}
+
+func TestRenderArt(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	if _, err := RenderArt("Hi", font, "", "notacolor", ""); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("RenderArt() error = %v, want %v", err, ErrInvalidColor)
+	}
+	if _, err := RenderArt("Hi", font, "", "", "middle"); err == nil {
+		t.Error("RenderArt() with an invalid alignment expected error")
+	}
+
+	got, err := RenderArt("Hi", font, "", "red", "")
+	if err != nil {
+		t.Fatalf("RenderArt() error = %v", err)
+	}
+	if want := GenerateArtWithFont("Hi", font, "", "red", ""); got != want {
+		t.Errorf("RenderArt() = %q, want %q", got, want)
+	}
+}
+
 func TestGenerateLineArt(t *testing.T) {
 	charMap, err := LoadBanner("../../assets/standard.txt")
 	if err != nil {
@@ -0,0 +1,104 @@
+package ascii
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Attr is a set of text attributes
+type Attr uint8
+
+// Text attributes, combinable with |
+const (
+	AttrBold Attr = 1 << iota
+	AttrDim
+	AttrItalic
+	AttrUnderline
+	AttrBlink
+	AttrInverse
+)
+
+// attrNames lists the attributes in SGR order with their names and SGR codes
+var attrNames = []struct {
+	attr Attr
+	name string
+	code int
+}{
+	{AttrBold, "bold", 1},
+	{AttrDim, "dim", 2},
+	{AttrItalic, "italic", 3},
+	{AttrUnderline, "underline", 4},
+	{AttrBlink, "blink", 5},
+	{AttrInverse, "inverse", 7},
+}
+
+// ParseAttrs parses a comma-separated list of attribute names such as
+// "bold,underline", ignoring case and spaces
+func ParseAttrs(spec string) (Attr, error) {
+	var attrs Attr
+	for _, item := range strings.Split(spec, ",") {
+		name := strings.ToLower(strings.TrimSpace(item))
+		found := false
+		for _, a := range attrNames {
+			if a.name == name {
+				attrs |= a.attr
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid attribute %q: expected bold, dim, italic, underline, blink or inverse", item)
+		}
+	}
+	return attrs, nil
+}
+
+// String returns the names of the attributes separated by commas
+func (a Attr) String() string {
+	var names []string
+	for _, attr := range attrNames {
+		if a&attr.attr != 0 {
+			names = append(names, attr.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// sgrParams returns the SGR parameters selecting the attributes
+func (a Attr) sgrParams() []string {
+	var params []string
+	for _, attr := range attrNames {
+		if a&attr.attr != 0 {
+			params = append(params, strconv.Itoa(attr.code))
+		}
+	}
+	return params
+}
+
+// css returns the CSS declarations rendering the attributes
+func (a Attr) css() []string {
+	var declarations []string
+	if a&AttrBold != 0 {
+		declarations = append(declarations, "font-weight:bold")
+	}
+	if a&AttrDim != 0 {
+		declarations = append(declarations, "opacity:0.5")
+	}
+	if a&AttrItalic != 0 {
+		declarations = append(declarations, "font-style:italic")
+	}
+	var decorations []string
+	if a&AttrUnderline != 0 {
+		decorations = append(decorations, "underline")
+	}
+	if a&AttrBlink != 0 {
+		decorations = append(decorations, "blink")
+	}
+	if len(decorations) > 0 {
+		declarations = append(declarations, "text-decoration:"+strings.Join(decorations, " "))
+	}
+	if a&AttrInverse != 0 {
+		declarations = append(declarations, "filter:invert(1)")
+	}
+	return declarations
+}
@@ -0,0 +1,188 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+
+	"ascii-art/assets"
+)
+
+func TestParseAttrs(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Attr
+		wantErr bool
+	}{
+		{"bold", AttrBold, false},
+		{"bold,underline", AttrBold | AttrUnderline, false},
+		{" Italic , DIM ", AttrItalic | AttrDim, false},
+		{"blink,inverse,bold", AttrBlink | AttrInverse | AttrBold, false},
+		{"bold,bold", AttrBold, false},
+		{"strike", 0, true},
+		{"bold,", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseAttrs(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAttrs(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAttrs(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttrString(t *testing.T) {
+	if got := (AttrUnderline | AttrBold).String(); got != "bold,underline" {
+		t.Errorf("String() = %q, want %q", got, "bold,underline")
+	}
+	if got := Attr(0).String(); got != "" {
+		t.Errorf("String() = %q, want empty", got)
+	}
+}
+
+func TestStyleSGRWithAttributes(t *testing.T) {
+	red := Color{Type: ColorBasic, Index: 1}
+	blue := Color{Type: ColorBasic, Index: 4}
+
+	tests := []struct {
+		style Style
+		want  string
+	}{
+		{Style{}, ""},
+		{Style{Foreground: red}, "\033[31m"},
+		{Style{Background: blue}, "\033[44m"},
+		{Style{Attrs: AttrBold}, "\033[1m"},
+		{Style{Foreground: red, Background: blue, Attrs: AttrBold | AttrUnderline}, "\033[1;4;31;44m"},
+		{Style{Background: RGBColor(1, 2, 3), Attrs: AttrInverse}, "\033[7;48;2;1;2;3m"},
+		{Style{Background: Color{Type: ColorIndexed, Index: 208}}, "\033[48;5;208m"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.style.sgr(); got != tt.want {
+			t.Errorf("%+v.sgr() = %q, want %q", tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestStyleCSS(t *testing.T) {
+	style := Style{
+		Foreground: RGBColor(255, 0, 0),
+		Background: RGBColor(0, 0, 255),
+		Attrs:      AttrBold | AttrItalic | AttrUnderline | AttrBlink,
+	}
+	want := "color:#ff0000;background-color:#0000ff;font-weight:bold;font-style:italic;text-decoration:underline blink"
+	if got := style.css(); got != want {
+		t.Errorf("css() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderBackgroundAndAttributes(t *testing.T) {
+	font, err := LoadFontFS(assets.Banners, "standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFontFS() error = %v", err)
+	}
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, options := range []Options{{Background: "nope"}, {Attributes: "bold,strike"}} {
+			if _, err := NewRenderer(font, options); err == nil {
+				t.Errorf("NewRenderer(%+v) succeeded, want an error", options)
+			}
+		}
+	})
+
+	t.Run("every styled run is reset before the terminator", func(t *testing.T) {
+		renderer, err := NewRenderer(font, Options{
+			Color: "white", Background: "blue", Attributes: "bold,underline",
+			Substring: "lo", Width: 200, Output: OutputDollar,
+		})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render("Hello")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+
+		for _, row := range result.Rows() {
+			if !strings.Contains(row, "\033[1;4;37;44m") {
+				t.Errorf("row %q does not select the style", row)
+			}
+			if strings.Count(row, "\033[") != 2*strings.Count(row, "\033[0m") {
+				t.Errorf("row %q has a styled run without a reset", row)
+			}
+			if last := strings.LastIndex(row, "\033["); !strings.HasSuffix(row, "$") || !strings.HasPrefix(row[last:], resetCode) {
+				t.Errorf("row %q should end with an unstyled $", row)
+			}
+		}
+
+		// Only the substring is styled: the "He" glyphs come first, unstyled
+		first := result.Canvas.Rows[0]
+		if !first[0].Style.IsZero() {
+			t.Errorf("cell of H has style %+v, want none", first[0].Style)
+		}
+		if last := first[len(first)-1]; last.Style.Background != (Color{Type: ColorBasic, Index: 4}) {
+			t.Errorf("cell of o has background %+v, want blue", last.Style.Background)
+		}
+	})
+
+	t.Run("rules keep the background", func(t *testing.T) {
+		renderer, err := NewRenderer(font, Options{
+			Background: "blue", Rules: []ColorRule{{Pattern: "i", Color: "red"}}, Width: 200,
+		})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render("Hi")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		blue, red := Color{Type: ColorBasic, Index: 4}, Color{Type: ColorBasic, Index: 1}
+		row := result.Canvas.Rows[1]
+		if got := row[len(row)-1].Style; got.Background != blue || got.Foreground != red {
+			t.Errorf("cell of i has style %+v, want red on blue", got)
+		}
+	})
+
+	t.Run("trim keeps background spaces", func(t *testing.T) {
+		renderer, err := NewRenderer(font, Options{Background: "blue", Width: 200, Output: OutputTrim})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render("i")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		plain, err := NewRenderer(font, Options{Width: 200, Output: OutputTrim})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		unstyled, err := plain.Render("i")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+
+		styled, trimmed := result.Encode(PlainEncoder{}), unstyled.Encode(PlainEncoder{})
+		if len(styled) <= len(trimmed) {
+			t.Errorf("trimmed output with a background %q should keep the spaces dropped from %q", styled, trimmed)
+		}
+	})
+
+	t.Run("no color profile drops attributes", func(t *testing.T) {
+		renderer, err := NewRenderer(font, Options{Background: "blue", Attributes: "bold", Width: 200})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render("Hi")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		if got := result.Encode(ANSIEncoder{Profile: ProfileNoColor}); strings.Contains(got, "\033[") {
+			t.Errorf("output without colors has escape sequences: %q", got)
+		}
+	})
+}
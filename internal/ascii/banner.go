@@ -3,18 +3,76 @@ package ascii
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
+// bannerHeaderPrefix marks the optional metrics line at the top of a banner file
+const bannerHeaderPrefix = "#"
+
+// bannerExtensions lists the file extensions tried when a banner is given by name
+var bannerExtensions = []string{".txt", ".flf"}
+
 // LoadBanner loads a banner file and returns a map of characters to their ASCII representations
 func LoadBanner(filename string) (map[rune][]string, error) {
+	font, err := LoadFont(filename)
+	if err != nil {
+		return nil, err
+	}
+	if font.Layout.Hardblank != 0 {
+		// Full-width rendering has no use for hardblanks
+		return replaceHardblanks(font.Glyphs, font.Layout.Hardblank), nil
+	}
+	return font.Glyphs, nil
+}
+
+// LoadFont loads a banner file together with its height, baseline and layout.
+// FIGlet fonts keep their hardblanks in the glyphs so that they can be fitted
+// and smushed; plain banners always use the full-width layout. Glyphs from an
+// overrides file next to the banner (e.g. "standard.overrides") replace or
+// extend the banner's own.
+func LoadFont(filename string) (*Font, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open banner file: %w", err)
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	font, err := readFont(fontName(filename), file)
+	if err != nil {
+		return nil, err
+	}
+	return applyOverrides(font, func() (io.ReadCloser, error) {
+		return os.Open(overridesFile(filename))
+	})
+}
+
+// LoadFontFS loads a banner file from a file system, such as the banners
+// embedded in the binary
+func LoadFontFS(fsys fs.FS, filename string) (*Font, error) {
+	file, err := fsys.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open banner file: %w", err)
+	}
+	defer file.Close()
+
+	font, err := readFont(fontName(filename), file)
+	if err != nil {
+		return nil, err
+	}
+	return applyOverrides(font, func() (io.ReadCloser, error) {
+		return fsys.Open(overridesFile(filename))
+	})
+}
+
+// readFont parses a plain or FIGlet banner
+func readFont(name string, r io.Reader) (*Font, error) {
+	scanner := bufio.NewScanner(r)
 	var lines []string
 	
 	// Read all lines from the file
@@ -26,46 +84,147 @@ func LoadBanner(filename string) (map[rune][]string, error) {
 		return nil, fmt.Errorf("failed to read banner file: %w", err)
 	}
 
-	return parseBannerLines(lines), nil
+	// FIGlet fonts are recognised by their header signature
+	if isFIGlet(lines) {
+		charMap, header, err := parseFIGletLines(lines)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse banner file: %w", err)
+		}
+		return &Font{
+			Name:     name,
+			Height:   header.Height,
+			Baseline: header.Baseline,
+			Layout:   header.layout(),
+			Fallback: FallbackSkip,
+			Glyphs:   charMap,
+		}, nil
+	}
+
+	font := &Font{
+		Name:     name,
+		Height:   defaultHeight,
+		Baseline: defaultBaseline,
+		Layout:   defaultLayout,
+		Fallback: FallbackSkip,
+	}
+	if len(lines) > 0 && strings.HasPrefix(lines[0], bannerHeaderPrefix) {
+		if err := parseBannerHeader(lines[0], font); err != nil {
+			return nil, fmt.Errorf("failed to parse banner header: %w", err)
+		}
+		lines = lines[1:]
+	}
+	font.Glyphs = parseBannerLines(lines, font.Height)
+
+	return font, nil
+}
+
+// fontName returns the banner name of a file path, e.g. "standard" for "assets/standard.txt"
+func fontName(filename string) string {
+	base := filepath.Base(filename)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// hasBannerExtension reports whether name ends with a supported banner extension
+func hasBannerExtension(name string) bool {
+	ext := filepath.Ext(name)
+	for _, known := range bannerExtensions {
+		if ext == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBannerHeader reads the optional first line of a banner file, which
+// declares the font metrics as key=value pairs, e.g. "# height=4 baseline=3".
+// Banners without a header are 8 rows tall. Unknown keys and a baseline
+// outside the font are rejected.
+func parseBannerHeader(line string, font *Font) error {
+	baselineSet := false
+	for _, field := range strings.Fields(strings.TrimPrefix(line, bannerHeaderPrefix)) {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			return fmt.Errorf("invalid field %q: expected key=value", field)
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", key, value)
+		}
+
+		switch key {
+		case "height":
+			font.Height = n
+		case "baseline":
+			font.Baseline = n
+			baselineSet = true
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+	}
+
+	if font.Height < 1 {
+		return fmt.Errorf("invalid height %d", font.Height)
+	}
+	if !baselineSet && font.Baseline > font.Height {
+		// Baseline defaults to the bottom row for fonts without descenders
+		font.Baseline = font.Height
+	}
+	if font.Baseline < 0 || font.Baseline > font.Height {
+		return fmt.Errorf("invalid baseline %d for height %d", font.Baseline, font.Height)
+	}
+	return nil
 }
 
-// parseBannerLines converts the banner file lines into a character map
-func parseBannerLines(lines []string) map[rune][]string {
+// parseBannerLines converts the banner file lines into a character map.
+// Glyphs follow each other in ASCII order from 32 (space) to 126 (~). A
+// separator line holding a code tag such as "U+00E9" declares the glyph for
+// that code point instead, so banners can cover characters beyond ASCII.
+func parseBannerLines(lines []string, height int) map[rune][]string {
 	charMap := make(map[rune][]string)
 	
 	// ASCII printable characters start from 32 (space) to 126 (~)
 	char := rune(32)
 	
-	for i := 0; i < len(lines); i += 9 { // Each character takes 8 lines + 1 separator
-		if i+8 >= len(lines) {
+	for i := 0; i < len(lines); i += height + 1 { // Each character takes height lines + 1 separator
+		if i+height >= len(lines) {
 			break
 		}
 		
-		// Extract 8 lines for this character (skip the first empty separator line)
-		charLines := make([]string, 8)
-		for j := 0; j < 8; j++ {
+		// Extract the lines for this character (skip the separator line)
+		charLines := make([]string, height)
+		for j := 0; j < height; j++ {
 			if i+j+1 < len(lines) {
 				charLines[j] = lines[i+j+1]
 			}
 		}
 		
-		// Special handling for underscore to make it fat like dash
-		if char == 95 { // underscore
-			// Make underscore fat by adding thickness
-			modifiedLines := make([]string, 8)
-			copy(modifiedLines, charLines)
-			modifiedLines[6] = " _______ " // Add line above (9 chars)
-			modifiedLines[7] = "|_______|" // Make it thick like dash (9 chars)
-			charMap[char] = modifiedLines
-		} else {
-			charMap[char] = charLines
+		// Tagged glyphs do not advance the ASCII sequence
+		if code, tagged := parseCodeTag(lines[i]); tagged {
+			charMap[code] = charLines
+			continue
 		}
-		char++
-		
 		if char > 126 {
-			break
+			continue
 		}
+		
+		charMap[char] = charLines
+		char++
 	}
 	
 	return charMap
-}
\ No newline at end of file
+}
+
+// parseCodeTag parses a glyph separator of the form "U+00E9"
+func parseCodeTag(separator string) (rune, bool) {
+	tag := strings.TrimSpace(separator)
+	if len(tag) < 3 || !strings.EqualFold(tag[:2], "U+") {
+		return 0, false
+	}
+
+	code, err := strconv.ParseUint(tag[2:], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return 0, false
+	}
+	return rune(code), true
+}
@@ -0,0 +1,51 @@
+package ascii
+
+// Cell is a single character position of a rendered canvas
+type Cell struct {
+	Rune   rune
+	Style  Style
+	Source int // index of the input rune that produced the cell; -1 for padding
+}
+
+// Canvas is the rendered form of a text: rows of styled cells. Rows may
+// differ in length; an empty input line is a row without cells.
+type Canvas struct {
+	Rows [][]Cell
+}
+
+// Width returns the length of the longest row
+func (c *Canvas) Width() int {
+	width := 0
+	for _, row := range c.Rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	return width
+}
+
+// Height returns the number of rows
+func (c *Canvas) Height() int {
+	return len(c.Rows)
+}
+
+// Text returns the runes of a row as a plain string
+func (c *Canvas) Text(row int) string {
+	chars := make([]rune, len(c.Rows[row]))
+	for i, cell := range c.Rows[row] {
+		chars[i] = cell.Rune
+	}
+	return string(chars)
+}
+
+// blankCells returns n unstyled padding cells
+func blankCells(n int) []Cell {
+	if n <= 0 {
+		return nil
+	}
+	cells := make([]Cell, n)
+	for i := range cells {
+		cells[i] = Cell{Rune: ' ', Source: -1}
+	}
+	return cells
+}
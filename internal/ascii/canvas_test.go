@@ -0,0 +1,93 @@
+package ascii
+
+import "testing"
+
+func TestRenderCanvasCells(t *testing.T) {
+	font := NewFont("mini", map[rune][]string{
+		'a': {"aa", "aa"},
+		'b': {"b", "b"},
+	})
+
+	renderer, err := NewRenderer(font, Options{Color: "red", Substring: "b", Width: 200})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	result, err := renderer.Render("ab\\nba")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	canvas := result.Canvas
+	if canvas.Height() != 4 || canvas.Width() != 3 {
+		t.Fatalf("Canvas is %dx%d, want 3x4", canvas.Width(), canvas.Height())
+	}
+	if got := canvas.Text(2); got != "baa" {
+		t.Errorf("Text(2) = %q, want %q", got, "baa")
+	}
+
+	// Source indices count the "\n" separator as two characters
+	wantSources := [][]int{{0, 0, 1}, {0, 0, 1}, {4, 5, 5}, {4, 5, 5}}
+	red, _ := LookupColor("red")
+	for i, row := range canvas.Rows {
+		for j, cell := range row {
+			if cell.Source != wantSources[i][j] {
+				t.Errorf("Cell (%d, %d) source = %d, want %d", i, j, cell.Source, wantSources[i][j])
+			}
+			colored := cell.Style.Foreground == red
+			if colored != (cell.Rune == 'b') {
+				t.Errorf("Cell (%d, %d) %q colored = %v", i, j, cell.Rune, colored)
+			}
+		}
+	}
+}
+
+func TestRenderCanvasAlignmentPadding(t *testing.T) {
+	font := NewFont("mini", map[rune][]string{'x': {"xx"}})
+
+	renderer, err := NewRenderer(font, Options{Alignment: "right", Width: 20, Output: OutputDollar})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	result, err := renderer.Render("x")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	row := result.Canvas.Rows[0]
+	if len(row) != 19 {
+		t.Fatalf("Right-aligned row has %d cells, want 19", len(row))
+	}
+	for _, cell := range row[:17] {
+		if cell.Rune != ' ' || cell.Source != -1 {
+			t.Fatalf("Padding cell = %+v, want blank with source -1", cell)
+		}
+	}
+	if row[18].Source != 0 {
+		t.Errorf("Glyph cell source = %d, want 0", row[18].Source)
+	}
+}
+
+func TestRenderJustifyKeepsColor(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	renderer, err := NewRenderer(font, Options{Color: "blue", Substring: "are", Alignment: "justify", Width: 100})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	result, err := renderer.Render("how are you")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	blue, _ := LookupColor("blue")
+	for _, cell := range result.Canvas.Rows[2] {
+		colored := cell.Style.Foreground == blue
+		inWord := cell.Source >= 4 && cell.Source < 7
+		if colored != inWord {
+			t.Fatalf("Cell %+v colored = %v, want %v", cell, colored, inWord)
+		}
+	}
+}
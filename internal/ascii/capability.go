@@ -0,0 +1,228 @@
+package ascii
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Color modes selecting when colors are written
+const (
+	ColorModeAuto   = "auto"   // detect what the output supports
+	ColorModeAlways = "always" // always write colors, at least the 16 basic ones
+	ColorModeNever  = "never"  // never write colors
+)
+
+// IsValidColorMode reports whether mode names a supported color mode
+func IsValidColorMode(mode string) bool {
+	switch mode {
+	case ColorModeAuto, ColorModeAlways, ColorModeNever:
+		return true
+	}
+	return false
+}
+
+// ColorProfile is the range of colors an output supports. The zero value
+// supports every color, so colors are written as they are specified.
+type ColorProfile uint8
+
+// Color profiles, from the most to the least capable
+const (
+	ProfileTrueColor ColorProfile = iota // 24-bit RGB colors
+	ProfileANSI256                       // the 256-color xterm palette
+	ProfileANSI                          // the 16 basic ANSI colors
+	ProfileNoColor                       // no colors or attributes at all
+)
+
+// DetectColorProfile returns the color profile of out under the given color
+// mode. A nil out stands for an output that is not a terminal, such as a file.
+//
+// In auto mode FORCE_COLOR takes precedence over NO_COLOR, which takes
+// precedence over out not being a terminal; otherwise COLORTERM and TERM
+// decide. FORCE_COLOR=0 disables colors and FORCE_COLOR=1, 2 or 3 requests
+// at least 16, 256 or 16 million colors. Always mode ignores the environment
+// switches and the terminal check but still honours COLORTERM and TERM.
+func DetectColorProfile(mode string, out *os.File) ColorProfile {
+	return detectColorProfile(mode, isTerminal(out), os.Getenv)
+}
+
+// detectColorProfile implements DetectColorProfile with the environment given by getenv
+func detectColorProfile(mode string, terminal bool, getenv func(string) string) ColorProfile {
+	profile := terminalColorProfile(getenv)
+
+	switch mode {
+	case ColorModeNever:
+		return ProfileNoColor
+	case ColorModeAlways:
+		return moreCapable(profile, ProfileANSI)
+	}
+
+	if force, set := forcedColorProfile(getenv); set {
+		if force == ProfileNoColor {
+			return ProfileNoColor
+		}
+		return moreCapable(profile, force)
+	}
+	if getenv("NO_COLOR") != "" || !terminal {
+		return ProfileNoColor
+	}
+	return profile
+}
+
+// terminalColorProfile derives the profile of the terminal from COLORTERM and TERM
+func terminalColorProfile(getenv func(string) string) ColorProfile {
+	term := strings.ToLower(getenv("TERM"))
+	if term == "dumb" {
+		return ProfileNoColor
+	}
+
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ProfileTrueColor
+	}
+
+	switch {
+	case strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor"):
+		return ProfileTrueColor
+	case strings.Contains(term, "256color"):
+		return ProfileANSI256
+	case term != "":
+		return ProfileANSI
+	case getenv("WT_SESSION") != "":
+		// Windows Terminal sets no TERM but supports 24-bit colors
+		return ProfileTrueColor
+	case runtime.GOOS == "windows":
+		return ProfileANSI
+	}
+	return ProfileNoColor
+}
+
+// forcedColorProfile interprets FORCE_COLOR, reporting whether it is set
+func forcedColorProfile(getenv func(string) string) (ColorProfile, bool) {
+	value := strings.ToLower(getenv("FORCE_COLOR"))
+	if value == "" {
+		return ProfileNoColor, false
+	}
+
+	switch value {
+	case "0", "false":
+		return ProfileNoColor, true
+	case "2":
+		return ProfileANSI256, true
+	case "3":
+		return ProfileTrueColor, true
+	}
+	return ProfileANSI, true
+}
+
+// moreCapable returns the profile supporting more colors
+func moreCapable(a, b ColorProfile) ColorProfile {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// isTerminal reports whether f is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Downsample returns the closest color the profile supports. Basic colors
+// are kept as they are by every profile that has colors.
+func (c Color) Downsample(profile ColorProfile) Color {
+	switch profile {
+	case ProfileNoColor:
+		return Color{}
+	case ProfileANSI256:
+		if c.Type == ColorRGB {
+			return Color{Type: ColorIndexed, Index: nearestIndexed(c.R, c.G, c.B)}
+		}
+	case ProfileANSI:
+		switch {
+		case c.Type == ColorIndexed && c.Index < 16:
+			return Color{Type: ColorBasic, Index: c.Index}
+		case c.Type == ColorIndexed || c.Type == ColorRGB:
+			r, g, b := c.RGB()
+			return Color{Type: ColorBasic, Index: nearestBasic(r, g, b)}
+		}
+	}
+	return c
+}
+
+// Downsample returns the style with both colors downsampled to the profile.
+// Without colors, attributes are dropped as well so that no escape sequences
+// are written at all.
+func (s Style) Downsample(profile ColorProfile) Style {
+	if profile == ProfileNoColor {
+		return Style{}
+	}
+	s.Foreground = s.Foreground.Downsample(profile)
+	s.Background = s.Background.Downsample(profile)
+	return s
+}
+
+// cubeLevels are the component values of the 6x6x6 xterm color cube
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// nearestIndexed returns the index of the xterm palette color closest to an
+// RGB color, choosing between the color cube and the grayscale ramp
+func nearestIndexed(r, g, b uint8) uint8 {
+	level := func(v uint8) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if absDiff(v, l) < absDiff(v, cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	ri, gi, bi := level(r), level(g), level(b)
+	cube := uint8(16 + 36*ri + 6*gi + bi)
+
+	average := (int(r) + int(g) + int(b)) / 3
+	step := (average - 3) / 10
+	if step < 0 {
+		step = 0
+	} else if step > 23 {
+		step = 23
+	}
+	gray := uint8(232 + step)
+
+	cubeR, cubeG, cubeB := Color{Type: ColorIndexed, Index: cube}.RGB()
+	grayR, grayG, grayB := Color{Type: ColorIndexed, Index: gray}.RGB()
+	if colorDistance(r, g, b, grayR, grayG, grayB) < colorDistance(r, g, b, cubeR, cubeG, cubeB) {
+		return gray
+	}
+	return cube
+}
+
+// nearestBasic returns the index of the basic ANSI color closest to an RGB color
+func nearestBasic(r, g, b uint8) uint8 {
+	best, bestDistance := 0, -1
+	for i, rgb := range basicPalette {
+		if d := colorDistance(r, g, b, rgb[0], rgb[1], rgb[2]); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return uint8(best)
+}
+
+// colorDistance returns the squared distance between two colors, weighting
+// the components roughly by how sensitive the eye is to them
+func colorDistance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(absDiff(r1, r2)), int(absDiff(g1, g2)), int(absDiff(b1, b2))
+	return 3*dr*dr + 4*dg*dg + 2*db*db
+}
+
+// absDiff returns the absolute difference of two components
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,87 @@
+package ascii
+
+import "testing"
+
+func TestDetectColorProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		terminal bool
+		env      map[string]string
+		want     ColorProfile
+	}{
+		{"truecolor terminal", ColorModeAuto, true, map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, ProfileTrueColor},
+		{"256-color terminal", ColorModeAuto, true, map[string]string{"TERM": "xterm-256color"}, ProfileANSI256},
+		{"basic terminal", ColorModeAuto, true, map[string]string{"TERM": "xterm"}, ProfileANSI},
+		{"direct color terminal", ColorModeAuto, true, map[string]string{"TERM": "xterm-direct"}, ProfileTrueColor},
+		{"dumb terminal", ColorModeAuto, true, map[string]string{"TERM": "dumb"}, ProfileNoColor},
+		{"pipe", ColorModeAuto, false, map[string]string{"TERM": "xterm-256color"}, ProfileNoColor},
+		{"NO_COLOR", ColorModeAuto, true, map[string]string{"TERM": "xterm", "NO_COLOR": "1"}, ProfileNoColor},
+		{"FORCE_COLOR on a pipe", ColorModeAuto, false, map[string]string{"TERM": "xterm-256color", "FORCE_COLOR": "1"}, ProfileANSI256},
+		{"FORCE_COLOR beats NO_COLOR", ColorModeAuto, true, map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "true"}, ProfileANSI},
+		{"FORCE_COLOR level", ColorModeAuto, false, map[string]string{"TERM": "xterm", "FORCE_COLOR": "3"}, ProfileTrueColor},
+		{"FORCE_COLOR=0", ColorModeAuto, true, map[string]string{"TERM": "xterm", "FORCE_COLOR": "0"}, ProfileNoColor},
+		{"always on a pipe", ColorModeAlways, false, map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, ProfileANSI256},
+		{"always on a dumb terminal", ColorModeAlways, true, map[string]string{"TERM": "dumb"}, ProfileANSI},
+		{"never", ColorModeNever, true, map[string]string{"COLORTERM": "truecolor", "FORCE_COLOR": "3"}, ProfileNoColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := detectColorProfile(tt.mode, tt.terminal, getenv); got != tt.want {
+				t.Errorf("detectColorProfile() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorDownsample(t *testing.T) {
+	red := Color{Type: ColorBasic, Index: 1}
+	orange := RGBColor(255, 136, 0)
+
+	tests := []struct {
+		name    string
+		color   Color
+		profile ColorProfile
+		want    Color
+	}{
+		{"truecolor keeps RGB", orange, ProfileTrueColor, orange},
+		{"RGB to 256 colors", orange, ProfileANSI256, Color{Type: ColorIndexed, Index: 208}},
+		{"gray to the grayscale ramp", RGBColor(128, 128, 128), ProfileANSI256, Color{Type: ColorIndexed, Index: 244}},
+		{"RGB to 16 colors", RGBColor(250, 5, 5), ProfileANSI, Color{Type: ColorBasic, Index: 9}},
+		{"indexed to 16 colors", Color{Type: ColorIndexed, Index: 21}, ProfileANSI, Color{Type: ColorBasic, Index: 4}},
+		{"low indexed to basic", Color{Type: ColorIndexed, Index: 4}, ProfileANSI, Color{Type: ColorBasic, Index: 4}},
+		{"basic kept", red, ProfileANSI, red},
+		{"no color", orange, ProfileNoColor, Color{}},
+		{"default kept", Color{}, ProfileANSI, Color{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.color.Downsample(tt.profile); got != tt.want {
+				t.Errorf("Downsample() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestANSIEncoderProfile(t *testing.T) {
+	orange := Style{Foreground: RGBColor(255, 136, 0)}
+	row := []Cell{{Rune: 'a', Style: orange}, {Rune: 'b', Style: orange}}
+
+	tests := []struct {
+		profile ColorProfile
+		want    string
+	}{
+		{ProfileTrueColor, "\033[38;2;255;136;0mab\033[0m"},
+		{ProfileANSI256, "\033[38;5;208mab\033[0m"},
+		{ProfileNoColor, "ab"},
+	}
+
+	for _, tt := range tests {
+		if got := (ANSIEncoder{Profile: tt.profile}).EncodeRow(row); got != tt.want {
+			t.Errorf("EncodeRow() with profile %d = %q, want %q", tt.profile, got, tt.want)
+		}
+	}
+}
@@ -1,76 +1,376 @@
 package ascii
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ColorType identifies how a Color is specified
+type ColorType uint8
+
+// Supported color types
+const (
+	ColorDefault ColorType = iota // the terminal's default color
+	ColorBasic                    // one of the 16 basic ANSI colors, Index 0-15
+	ColorIndexed                  // one of the 256 xterm colors, Index 0-255
+	ColorRGB                      // a 24-bit truecolor given by R, G and B
+)
+
+// Color is a foreground or background color. The zero value is the terminal's default color.
+type Color struct {
+	Type    ColorType
+	Index   uint8 // palette index of basic and indexed colors
+	R, G, B uint8 // components of RGB colors
+}
+
+// ErrInvalidColor is returned for color specifications that cannot be parsed
+var ErrInvalidColor = errors.New("invalid color")
+
+// Style is the appearance of a single cell. The zero value is unstyled.
+type Style struct {
+	Foreground Color
+	Background Color
+	Attrs      Attr
+}
+
+// resetCode ends every active ANSI style
+const resetCode = "\033[0m"
+
+// namedColors maps the supported color names to colors
+var namedColors = map[string]Color{
+	"red":     {Type: ColorBasic, Index: 1},
+	"green":   {Type: ColorBasic, Index: 2},
+	"yellow":  {Type: ColorBasic, Index: 3},
+	"blue":    {Type: ColorBasic, Index: 4},
+	"magenta": {Type: ColorBasic, Index: 5},
+	"cyan":    {Type: ColorBasic, Index: 6},
+	"white":   {Type: ColorBasic, Index: 7},
+	"orange":  {Type: ColorIndexed, Index: 208},
+}
+
+// basicPalette holds the xterm RGB values of the 16 basic ANSI colors
+var basicPalette = [16][3]uint8{
+	{0x00, 0x00, 0x00}, {0xcd, 0x00, 0x00}, {0x00, 0xcd, 0x00}, {0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee}, {0xcd, 0x00, 0xcd}, {0x00, 0xcd, 0xcd}, {0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f}, {0xff, 0x00, 0x00}, {0x00, 0xff, 0x00}, {0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff}, {0xff, 0x00, 0xff}, {0x00, 0xff, 0xff}, {0xff, 0xff, 0xff},
+}
+
+// LookupColor returns the color with the given name, ignoring case. The names
+// of the basic ANSI colors and orange select palette colors so that they
+// follow the terminal's theme; every other CSS color name selects its RGB value.
+func LookupColor(name string) (Color, bool) {
+	name = strings.ToLower(name)
+	if color, exists := namedColors[name]; exists {
+		return color, true
+	}
+	if rgb, exists := cssColors[name]; exists {
+		return RGBColor(rgb[0], rgb[1], rgb[2]), true
+	}
+	return Color{}, false
+}
+
+// RGBColor returns the truecolor with the given components
+func RGBColor(r, g, b uint8) Color {
+	return Color{Type: ColorRGB, R: r, G: g, B: b}
+}
+
+// ParseColor parses a color specification, ignoring case and surrounding spaces:
+//
+//	red, rebeccapurple      a color name (see LookupColor)
+//	#f80, #ff8800           CSS hex notation
+//	rgb(255, 136, 0)        red, green and blue from 0 to 255 or as percentages
+//	hsl(32, 100%, 50%)      hue in degrees, saturation and lightness in percent
+//	ansi256:208             an index into the 256-color xterm palette
+//
+// The returned error wraps ErrInvalidColor.
+func ParseColor(spec string) (Color, error) {
+	s := strings.ToLower(strings.TrimSpace(spec))
+	invalid := func(reason string) (Color, error) {
+		return Color{}, fmt.Errorf("%w %q: %s", ErrInvalidColor, spec, reason)
+	}
+
+	switch {
+	case s == "":
+		return invalid("empty color")
+
+	case strings.HasPrefix(s, "#"):
+		digits := s[1:]
+		if len(digits) == 3 {
+			digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+		}
+		value, err := strconv.ParseUint(digits, 16, 32)
+		if len(digits) != 6 || err != nil {
+			return invalid("expected #rgb or #rrggbb")
+		}
+		return RGBColor(uint8(value>>16), uint8(value>>8), uint8(value)), nil
+
+	case strings.HasPrefix(s, "ansi256:"):
+		index, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(s, "ansi256:")))
+		if err != nil || index < 0 || index > 255 {
+			return invalid("expected an index from 0 to 255")
+		}
+		return Color{Type: ColorIndexed, Index: uint8(index)}, nil
+
+	case strings.HasPrefix(s, "rgb(") || strings.HasPrefix(s, "hsl("):
+		args, ok := colorFunctionArgs(s[4:])
+		if !ok {
+			return invalid("expected three comma-separated values")
+		}
+		if s[0] == 'r' {
+			var rgb [3]uint8
+			for i, arg := range args {
+				value, ok := parseColorComponent(arg, 255)
+				if !ok {
+					return invalid(fmt.Sprintf("%q is not a value from 0 to 255 or 0%% to 100%%", arg))
+				}
+				rgb[i] = uint8(math.Round(value))
+			}
+			return RGBColor(rgb[0], rgb[1], rgb[2]), nil
+		}
+
+		hue, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+		if err != nil {
+			return invalid(fmt.Sprintf("%q is not a hue in degrees", args[0]))
+		}
+		var sl [2]float64
+		for i, arg := range args[1:] {
+			if !strings.HasSuffix(arg, "%") {
+				return invalid(fmt.Sprintf("%q is not a percentage", arg))
+			}
+			value, ok := parseColorComponent(arg, 1)
+			if !ok {
+				return invalid(fmt.Sprintf("%q is not a percentage from 0%% to 100%%", arg))
+			}
+			sl[i] = value
+		}
+		r, g, b := hslToRGB(hue, sl[0], sl[1])
+		return RGBColor(r, g, b), nil
+	}
+
+	if color, exists := LookupColor(s); exists {
+		return color, nil
+	}
+	return invalid("unknown color name")
+}
+
+// colorFunctionArgs splits the arguments of rgb() or hsl(), given the text
+// after the opening parenthesis. Commas or spaces separate the arguments.
+func colorFunctionArgs(s string) ([]string, bool) {
+	if !strings.HasSuffix(s, ")") {
+		return nil, false
+	}
+	args := strings.FieldsFunc(strings.TrimSuffix(s, ")"), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	return args, len(args) == 3
+}
+
+// parseColorComponent parses a number from 0 to max or a percentage of max
+func parseColorComponent(s string, max float64) (float64, bool) {
+	percent := strings.HasSuffix(s, "%")
+	value, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	if percent {
+		value = value * max / 100
+	}
+	return value, value >= 0 && value <= max
+}
+
+// hslToRGB converts a hue in degrees and saturation and lightness from 0 to 1 to RGB
+func hslToRGB(hue, saturation, lightness float64) (r, g, b uint8) {
+	hue = math.Mod(hue, 360)
+	if hue < 0 {
+		hue += 360
+	}
+
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	var rf, gf, bf float64
+	switch {
+	case hue < 60:
+		rf, gf = chroma, x
+	case hue < 120:
+		rf, gf = x, chroma
+	case hue < 180:
+		gf, bf = chroma, x
+	case hue < 240:
+		gf, bf = x, chroma
+	case hue < 300:
+		rf, bf = x, chroma
+	default:
+		rf, bf = chroma, x
+	}
+
+	m := lightness - chroma/2
+	channel := func(v float64) uint8 {
+		return uint8(math.Round((v + m) * 255))
+	}
+	return channel(rf), channel(gf), channel(bf)
+}
+
+// IsDefault reports whether c is the terminal's default color
+func (c Color) IsDefault() bool {
+	return c.Type == ColorDefault
+}
+
+// RGB returns the red, green and blue components of the color, using the
+// xterm palette for palette colors. The default color has no components and
+// returns black.
+func (c Color) RGB() (r, g, b uint8) {
+	switch c.Type {
+	case ColorRGB:
+		return c.R, c.G, c.B
+	case ColorBasic:
+		rgb := basicPalette[c.Index&15]
+		return rgb[0], rgb[1], rgb[2]
+	case ColorIndexed:
+		if c.Index < 16 {
+			rgb := basicPalette[c.Index]
+			return rgb[0], rgb[1], rgb[2]
+		}
+		if c.Index >= 232 {
+			level := 8 + 10*(c.Index-232)
+			return level, level, level
+		}
+		// 6x6x6 color cube
+		cube := func(v uint8) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return 55 + 40*v
+		}
+		i := c.Index - 16
+		return cube(i / 36), cube(i / 6 % 6), cube(i % 6)
+	}
+	return 0, 0, 0
+}
+
+// hex returns the color in CSS #rrggbb notation
+func (c Color) hex() string {
+	r, g, b := c.RGB()
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// sgrParams returns the SGR parameters selecting the color as a foreground or background
+func (c Color) sgrParams(background bool) []string {
+	base := 30
+	if background {
+		base = 40
+	}
+
+	switch c.Type {
+	case ColorBasic:
+		if c.Index < 8 {
+			return []string{strconv.Itoa(base + int(c.Index))}
+		}
+		return []string{strconv.Itoa(base + 60 + int(c.Index&15) - 8)}
+	case ColorIndexed:
+		return []string{strconv.Itoa(base + 8), "5", strconv.Itoa(int(c.Index))}
+	case ColorRGB:
+		return []string{strconv.Itoa(base + 8), "2", strconv.Itoa(int(c.R)), strconv.Itoa(int(c.G)), strconv.Itoa(int(c.B))}
+	}
+	return nil
+}
+
+// IsZero reports whether the style leaves the cell unstyled
+func (s Style) IsZero() bool {
+	return s == Style{}
+}
+
+// sgr returns the ANSI escape sequence that selects the style, or "" for the zero style
+func (s Style) sgr() string {
+	params := s.Attrs.sgrParams()
+	params = append(params, s.Foreground.sgrParams(false)...)
+	params = append(params, s.Background.sgrParams(true)...)
+	if len(params) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
 
 // ApplyColor applies color to specific substring in ASCII art
+//
+// Deprecated: an invalid color cannot be reported and leaves the art lines
+// unchanged. Use ColorArt instead.
 func ApplyColor(artLines []string, substring, color, originalText string, charMap map[rune][]string) []string {
-	colorCode, exists := colorMap[strings.ToLower(color)]
-	if !exists {
+	colored, err := ColorArt(artLines, substring, color, originalText, charMap)
+	if err != nil {
 		return artLines
 	}
+	return colored
+}
+
+// ColorArt applies color to specific substring in ASCII art, returning an
+// error for a color ParseColor does not accept. Positions are counted in
+// runes, so multi-byte text and glyphs line up.
+func ColorArt(artLines []string, substring, color, originalText string, charMap map[rune][]string) ([]string, error) {
+	c, err := ParseColor(color)
+	if err != nil {
+		return nil, err
+	}
+	colorCode := Style{Foreground: c}.sgr()
 
 	if substring == "" {
 		// Color entire output
 		for i := range artLines {
 			if artLines[i] != "" && artLines[i] != "$" {
 				line := strings.TrimSuffix(artLines[i], "$")
-				artLines[i] = colorCode + line + colorMap["reset"] + "$"
+				artLines[i] = colorCode + line + resetCode + "$"
 			}
 		}
-		return artLines
+		return artLines, nil
 	}
 
 	// Find all occurrences of substring in original text
 	indices := findSubstringIndices(originalText, substring)
 	if len(indices) == 0 {
-		return artLines
+		return artLines, nil
 	}
 
 	// Apply color to all occurrences at once
-	return colorAllSubstrings(artLines, indices, len(substring), colorCode, originalText, charMap)
+	return colorAllSubstrings(artLines, indices, utf8.RuneCountInString(substring), colorCode, originalText, charMap), nil
 }
 
-// findSubstringIndices finds all starting indices of substring in text
+// findSubstringIndices finds the rune index of every occurrence of substring
+// in text, including overlapping ones
 func findSubstringIndices(text, substring string) []int {
 	var indices []int
-	start := 0
-	for {
-		idx := strings.Index(text[start:], substring)
-		if idx == -1 {
-			break
-		}
-		indices = append(indices, start+idx)
-		start += idx + 1
+	for _, match := range literalSpans([]rune(substring), []rune(text), false) {
+		indices = append(indices, match.start)
 	}
 	return indices
 }
 
-// colorAllSubstrings applies color to all substring occurrences at once
+// colorAllSubstrings colors the glyph columns of the length runes starting
+// at every index of originalText. Overlapping occurrences share one colored run.
 func colorAllSubstrings(artLines []string, indices []int, length int, colorCode, originalText string, charMap map[rune][]string) []string {
-	// Calculate pixel positions for all occurrences
-	type colorRange struct {
-		start, end int
-	}
-	var ranges []colorRange
-
-	for _, startIdx := range indices {
-		pixelStart := 0
-		for i := 0; i < startIdx; i++ {
-			if i < len(originalText) {
-				if charLines, exists := charMap[rune(originalText[i])]; exists && len(charLines) > 0 {
-					pixelStart += len(charLines[0])
-				}
-			}
+	// Find the first column of every character's glyph
+	chars := []rune(originalText)
+	columns := make([]int, len(chars)+1)
+	for i, char := range chars {
+		columns[i+1] = columns[i]
+		if charLines, exists := charMap[char]; exists {
+			columns[i+1] += glyphWidth(charLines)
 		}
+	}
 
-		pixelEnd := pixelStart
-		for i := startIdx; i < startIdx+length && i < len(originalText); i++ {
-			if charLines, exists := charMap[rune(originalText[i])]; exists && len(charLines) > 0 {
-				pixelEnd += len(charLines[0])
-			}
+	// Mark the columns of every occurrence
+	colored := make([]bool, columns[len(chars)])
+	for _, start := range indices {
+		end := start + length
+		if end > len(chars) {
+			end = len(chars)
+		}
+		for col := columns[start]; col < columns[end]; col++ {
+			colored[col] = true
 		}
-
-		ranges = append(ranges, colorRange{pixelStart, pixelEnd})
 	}
 
 	// Apply color to each line
@@ -79,28 +379,26 @@ func colorAllSubstrings(artLines []string, indices []int, length int, colorCode,
 			continue
 		}
 
-		line := strings.TrimSuffix(artLines[i], "$")
-		result := ""
-		lastPos := 0
-
-		for _, r := range ranges {
-			if r.start < len(line) && r.end <= len(line) {
-				// Add text before colored section
-				if r.start > lastPos {
-					result += line[lastPos:r.start]
+		line := []rune(strings.TrimSuffix(artLines[i], "$"))
+		var sb strings.Builder
+		inColor := false
+		for col, char := range line {
+			isColored := col < len(colored) && colored[col]
+			if isColored != inColor {
+				if isColored {
+					sb.WriteString(colorCode)
+				} else {
+					sb.WriteString(resetCode)
 				}
-				// Add colored section
-				result += colorCode + line[r.start:r.end] + colorMap["reset"]
-				lastPos = r.end
+				inColor = isColored
 			}
+			sb.WriteRune(char)
 		}
-
-		// Add remaining text
-		if lastPos < len(line) {
-			result += line[lastPos:]
+		if inColor {
+			sb.WriteString(resetCode)
 		}
 
-		artLines[i] = result + "$"
+		artLines[i] = sb.String() + "$"
 	}
 
 	return artLines
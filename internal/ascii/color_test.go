@@ -1,8 +1,11 @@
 package ascii
 
 import (
+	"errors"
 	"strings"
 	"testing"
+
+	"ascii-art/assets"
 )
 
 func TestApplyColor(t *testing.T) {
@@ -56,6 +59,50 @@ func TestApplyColor(t *testing.T) {
 	}
 }
 
+func TestApplyColorMultiByte(t *testing.T) {
+	charMap := map[rune][]string{
+		'a': {"aa", "aa"},
+		'é': {"é_", "_é"},
+		'ö': {"ööö", "ööö"},
+	}
+	blue := Style{Foreground: Color{Type: ColorBasic, Index: 4}}.sgr()
+
+	tests := []struct {
+		name      string
+		text      string
+		substring string
+		want      []string
+	}{
+		{
+			name:      "Multi-byte character",
+			text:      "aéa",
+			substring: "é",
+			want:      []string{"aa" + blue + "é_" + resetCode + "aa$", "aa" + blue + "_é" + resetCode + "aa$"},
+		},
+		{
+			name:      "Occurrence after multi-byte characters",
+			text:      "öéa",
+			substring: "a",
+			want:      []string{"ööö" + "é_" + blue + "aa" + resetCode + "$", "ööö" + "_é" + blue + "aa" + resetCode + "$"},
+		},
+		{
+			name:      "Overlapping occurrences",
+			text:      "aaaö",
+			substring: "aa",
+			want:      []string{blue + "aaaaaa" + resetCode + "ööö$", blue + "aaaaaa" + resetCode + "ööö$"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyColor(generateLineArt(tt.text, charMap), tt.substring, "blue", tt.text, charMap)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ApplyColor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFindSubstringIndices(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -67,6 +114,8 @@ func TestFindSubstringIndices(t *testing.T) {
 		{"Multiple occurrences", "a king kitten have kit", "kit", []int{7, 19}},
 		{"No occurrence", "hello", "xyz", []int{}},
 		{"Overlapping", "aaa", "aa", []int{0, 1}},
+		{"Multi-byte text", "café café", "é", []int{3, 8}},
+		{"Multi-byte substring", "naïve naïf", "ïf", []int{8}},
 	}
 
 	for _, tt := range tests {
@@ -84,3 +133,146 @@ func TestFindSubstringIndices(t *testing.T) {
 		})
 	}
 }
+
+func TestStyleSGR(t *testing.T) {
+	red, _ := LookupColor("RED")
+	orange, _ := LookupColor("orange")
+
+	tests := []struct {
+		name  string
+		style Style
+		want  string
+	}{
+		{"zero style", Style{}, ""},
+		{"basic foreground", Style{Foreground: red}, "\033[31m"},
+		{"indexed foreground", Style{Foreground: orange}, "\033[38;5;208m"},
+		{"bright background", Style{Background: Color{Type: ColorBasic, Index: 12}}, "\033[104m"},
+		{"foreground and background", Style{Foreground: red, Background: orange}, "\033[31;48;5;208m"},
+		{"truecolor foreground", Style{Foreground: RGBColor(255, 136, 0)}, "\033[38;2;255;136;0m"},
+		{"truecolor background", Style{Background: RGBColor(1, 2, 3)}, "\033[48;2;1;2;3m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.sgr(); got != tt.want {
+				t.Errorf("sgr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := LookupColor("notacolor"); ok {
+		t.Error("LookupColor(\"notacolor\") should fail")
+	}
+}
+
+func TestColorRGB(t *testing.T) {
+	tests := []struct {
+		color Color
+		want  string
+	}{
+		{Color{Type: ColorBasic, Index: 1}, "#cd0000"},
+		{Color{Type: ColorIndexed, Index: 9}, "#ff0000"},
+		{Color{Type: ColorIndexed, Index: 208}, "#ff8700"},
+		{Color{Type: ColorIndexed, Index: 16}, "#000000"},
+		{Color{Type: ColorIndexed, Index: 244}, "#808080"},
+		{RGBColor(0x12, 0xab, 0xef), "#12abef"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.color.hex(); got != tt.want {
+			t.Errorf("hex() of %+v = %q, want %q", tt.color, got, tt.want)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	orange := RGBColor(255, 136, 0)
+
+	tests := []struct {
+		spec    string
+		want    Color
+		wantErr bool
+	}{
+		{"red", Color{Type: ColorBasic, Index: 1}, false},
+		{"Orange", Color{Type: ColorIndexed, Index: 208}, false},
+		{"rebeccapurple", RGBColor(0x66, 0x33, 0x99), false},
+		{"DarkSlateGrey", RGBColor(0x2f, 0x4f, 0x4f), false},
+		{"#ff8800", orange, false},
+		{"#F80", orange, false},
+		{"rgb(255,136,0)", orange, false},
+		{" rgb(255, 136, 0) ", orange, false},
+		{"rgb(100%, 0%, 50%)", RGBColor(255, 0, 128), false},
+		{"hsl(32, 100%, 50%)", orange, false},
+		{"hsl(0deg 0% 100%)", RGBColor(255, 255, 255), false},
+		{"hsl(-120, 100%, 50%)", RGBColor(0, 0, 255), false},
+		{"ansi256:208", Color{Type: ColorIndexed, Index: 208}, false},
+		{"ansi256:0", Color{Type: ColorIndexed, Index: 0}, false},
+		{"", Color{}, true},
+		{"notacolor", Color{}, true},
+		{"#ff88", Color{}, true},
+		{"#gg8800", Color{}, true},
+		{"rgb(256,0,0)", Color{}, true},
+		{"rgb(1,2)", Color{}, true},
+		{"rgb(1,2,3", Color{}, true},
+		{"hsl(32, 100, 50%)", Color{}, true},
+		{"hsl(32, 120%, 50%)", Color{}, true},
+		{"ansi256:256", Color{}, true},
+		{"ansi256:x", Color{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseColor(tt.spec)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidColor) {
+					t.Errorf("ParseColor(%q) error = %v, want %v", tt.spec, err, ErrInvalidColor)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseColor(%q) error = %v", tt.spec, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColor(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRendererRejectsUnknownColor(t *testing.T) {
+	font, err := LoadFontFS(assets.Banners, "standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFontFS() error = %v", err)
+	}
+
+	if _, err := NewRenderer(font, Options{Color: "notacolor"}); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("NewRenderer() error = %v, want %v", err, ErrInvalidColor)
+	}
+
+	renderer, err := NewRenderer(font, Options{Color: "#ff8800", Width: 200})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	result, err := renderer.Render("A")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(result.String(), "\033[38;2;255;136;0m") {
+		t.Errorf("Render() output lacks the truecolor sequence: %q", result.String())
+	}
+}
+
+func TestColorArtRejectsUnknownColor(t *testing.T) {
+	lines := []string{"test$"}
+	if _, err := ColorArt(lines, "", "notacolor", "t", nil); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("ColorArt() error = %v, want %v", err, ErrInvalidColor)
+	}
+
+	got, err := ColorArt(lines, "", "#ff8800", "t", nil)
+	if err != nil {
+		t.Fatalf("ColorArt() error = %v", err)
+	}
+	if want := "\033[38;2;255;136;0mtest\033[0m$"; got[0] != want {
+		t.Errorf("ColorArt() = %q, want %q", got[0], want)
+	}
+}
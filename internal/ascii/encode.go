@@ -0,0 +1,160 @@
+package ascii
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Output modes controlling how every rendered row ends
+const (
+	OutputDollar = "dollar" // rows end with a $ marker
+	OutputNone   = "none"   // rows end without a marker
+	OutputTrim   = "trim"   // rows end without a marker and trailing whitespace is removed
+)
+
+// IsValidOutputMode reports whether mode names a supported output mode
+func IsValidOutputMode(mode string) bool {
+	switch mode {
+	case OutputDollar, OutputNone, OutputTrim:
+		return true
+	}
+	return false
+}
+
+// Encoder serializes the rows of a canvas into an output format
+type Encoder interface {
+	EncodeRow(row []Cell) string
+}
+
+// PlainEncoder writes the runes of each cell and drops all styling
+type PlainEncoder struct{}
+
+// ANSIEncoder writes styled cells using ANSI escape sequences, downsampling
+// colors to Profile. The zero value writes every color as specified.
+type ANSIEncoder struct {
+	Profile ColorProfile
+}
+
+// HTMLEncoder writes HTML-escaped text, wrapping styled runs in <span>
+// elements with inline CSS. Rows are meant to be placed inside a <pre> element.
+type HTMLEncoder struct{}
+
+// Encode serializes the canvas with enc, ending every row as the output mode
+// requires, and joins the rows with newlines. An empty mode means OutputNone.
+func (c *Canvas) Encode(enc Encoder, mode string) string {
+	rows := make([]string, len(c.Rows))
+	for i, row := range c.Rows {
+		if mode == OutputTrim {
+			row = trimCells(row)
+		}
+		rows[i] = enc.EncodeRow(row)
+		if mode == OutputDollar {
+			rows[i] += "$"
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// trimCells drops the trailing whitespace cells of a row. Trimming cells
+// rather than encoded text keeps escape sequences and markup intact. Cells
+// with a background, or underlined or inverted ones, show even as spaces and
+// are kept.
+func trimCells(row []Cell) []Cell {
+	end := len(row)
+	for end > 0 && unicode.IsSpace(row[end-1].Rune) && !row[end-1].Style.showsOnSpace() {
+		end--
+	}
+	return row[:end]
+}
+
+// showsOnSpace reports whether the style is visible on a space
+func (s Style) showsOnSpace() bool {
+	return !s.Background.IsDefault() || s.Attrs&(AttrUnderline|AttrInverse) != 0
+}
+
+// EncodeRow returns the runes of the row
+func (PlainEncoder) EncodeRow(row []Cell) string {
+	var sb strings.Builder
+	for _, cell := range row {
+		sb.WriteRune(cell.Rune)
+	}
+	return sb.String()
+}
+
+// EncodeRow returns the row with an escape sequence at every style change
+func (e ANSIEncoder) EncodeRow(row []Cell) string {
+	if e.Profile != ProfileTrueColor {
+		downsampled := make([]Cell, len(row))
+		for i, cell := range row {
+			cell.Style = cell.Style.Downsample(e.Profile)
+			downsampled[i] = cell
+		}
+		row = downsampled
+	}
+
+	var sb strings.Builder
+	for _, run := range styleRuns(row) {
+		if run.style.IsZero() {
+			sb.WriteString(run.text)
+			continue
+		}
+		sb.WriteString(run.style.sgr())
+		sb.WriteString(run.text)
+		sb.WriteString(resetCode)
+	}
+	return sb.String()
+}
+
+// EncodeRow returns the row as escaped HTML with a <span> around every styled run
+func (HTMLEncoder) EncodeRow(row []Cell) string {
+	var sb strings.Builder
+	for _, run := range styleRuns(row) {
+		if run.style.IsZero() {
+			sb.WriteString(html.EscapeString(run.text))
+			continue
+		}
+		sb.WriteString(`<span style="`)
+		sb.WriteString(run.style.css())
+		sb.WriteString(`">`)
+		sb.WriteString(html.EscapeString(run.text))
+		sb.WriteString("</span>")
+	}
+	return sb.String()
+}
+
+// css returns the inline CSS declarations for the style
+func (s Style) css() string {
+	var declarations []string
+	if !s.Foreground.IsDefault() {
+		declarations = append(declarations, "color:"+s.Foreground.hex())
+	}
+	if !s.Background.IsDefault() {
+		declarations = append(declarations, "background-color:"+s.Background.hex())
+	}
+	declarations = append(declarations, s.Attrs.css()...)
+	return strings.Join(declarations, ";")
+}
+
+// styleRun is a sequence of adjacent cells sharing a style
+type styleRun struct {
+	style Style
+	text  string
+}
+
+// styleRuns splits a row into runs of identically styled cells
+func styleRuns(row []Cell) []styleRun {
+	var runs []styleRun
+	var sb strings.Builder
+	for i, cell := range row {
+		if i > 0 && cell.Style != row[i-1].Style {
+			runs = append(runs, styleRun{style: row[i-1].Style, text: sb.String()})
+			sb.Reset()
+		}
+		sb.WriteRune(cell.Rune)
+	}
+	if len(row) > 0 {
+		runs = append(runs, styleRun{style: row[len(row)-1].Style, text: sb.String()})
+	}
+	return runs
+}
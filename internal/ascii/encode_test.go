@@ -0,0 +1,71 @@
+package ascii
+
+import "testing"
+
+func TestEncoders(t *testing.T) {
+	red := Style{Foreground: Color{Type: ColorBasic, Index: 1}}
+	canvas := &Canvas{Rows: [][]Cell{
+		{{Rune: '<', Source: 0}, {Rune: 'a', Style: red, Source: 1}, {Rune: 'b', Style: red, Source: 2}, {Rune: '>', Source: 3}},
+		nil,
+	}}
+
+	tests := []struct {
+		name    string
+		encoder Encoder
+		mode    string
+		want    string
+	}{
+		{"plain", PlainEncoder{}, "", "<ab>\n"},
+		{"plain with dollar", PlainEncoder{}, OutputDollar, "<ab>$\n$"},
+		{"ansi", ANSIEncoder{}, OutputDollar, "<\033[31mab\033[0m>$\n$"},
+		{"html", HTMLEncoder{}, "", "&lt;<span style=\"color:#cd0000\">ab</span>&gt;\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canvas.Encode(tt.encoder, tt.mode); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLEncoderBackground(t *testing.T) {
+	style := Style{
+		Foreground: Color{Type: ColorIndexed, Index: 208},
+		Background: Color{Type: ColorBasic, Index: 4},
+	}
+	row := []Cell{{Rune: '&', Style: style}}
+
+	want := `<span style="color:#ff8700;background-color:#0000ee">&amp;</span>`
+	if got := (HTMLEncoder{}).EncodeRow(row); got != want {
+		t.Errorf("EncodeRow() = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeTrim(t *testing.T) {
+	red := Style{Foreground: Color{Type: ColorBasic, Index: 1}}
+	canvas := &Canvas{Rows: [][]Cell{
+		append([]Cell{{Rune: 'a', Style: red}, {Rune: ' ', Style: red}}, blankCells(3)...),
+		blankCells(2),
+	}}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{OutputDollar, "\033[31ma \033[0m   $\n  $"},
+		{OutputNone, "\033[31ma \033[0m   \n  "},
+		{OutputTrim, "\033[31ma\033[0m\n"},
+	}
+
+	for _, tt := range tests {
+		if got := canvas.Encode(ANSIEncoder{}, tt.mode); got != tt.want {
+			t.Errorf("Encode() in mode %q = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+
+	if !IsValidOutputMode(OutputTrim) || IsValidOutputMode("crlf") {
+		t.Error("IsValidOutputMode() reports wrong modes")
+	}
+}
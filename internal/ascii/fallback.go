@@ -0,0 +1,99 @@
+package ascii
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fallback policies for characters the font has no glyph for
+const (
+	FallbackSkip          = "skip"          // drop the character (default)
+	FallbackPlaceholder   = "placeholder"   // render the placeholder glyph instead
+	FallbackTransliterate = "transliterate" // render an ASCII approximation, e.g. é as e
+	FallbackError         = "error"         // reject the text
+)
+
+// defaultPlaceholder is the character rendered in place of a missing glyph
+const defaultPlaceholder = '?'
+
+// IsValidFallback reports whether policy names a supported fallback policy
+func IsValidFallback(policy string) bool {
+	switch policy {
+	case FallbackSkip, FallbackPlaceholder, FallbackTransliterate, FallbackError:
+		return true
+	}
+	return false
+}
+
+// MissingGlyphError reports a character that the font cannot render
+type MissingGlyphError struct {
+	Char     rune
+	Position int // 1-based rune position in the input text
+}
+
+func (e *MissingGlyphError) Error() string {
+	return fmt.Sprintf("no glyph for %q (U+%04X) at position %d", e.Char, e.Char, e.Position)
+}
+
+// CheckCoverage returns a *MissingGlyphError for the first character of text
+// that the font cannot render, even after applying its fallback policy. The
+// literal "\n" line separators count as two characters of the text.
+func CheckCoverage(text string, font *Font) error {
+	position := 0
+	for _, line := range strings.Split(text, "\\n") {
+		for _, char := range line {
+			position++
+			if _, exists := font.glyph(char); !exists {
+				return &MissingGlyphError{Char: char, Position: position}
+			}
+		}
+		position += 2 // the "\n" separator
+	}
+	return nil
+}
+
+// glyph returns the glyph used to render char, applying the font's fallback
+// policy when the font has no glyph for it
+func (f *Font) glyph(char rune) ([]string, bool) {
+	if charLines, exists := f.Glyphs[char]; exists {
+		return charLines, true
+	}
+
+	switch f.Fallback {
+	case FallbackPlaceholder:
+		return f.placeholderGlyph()
+	case FallbackTransliterate:
+		if replacement, ok := transliterations[char]; ok {
+			if charLines, ok := f.composeGlyph(replacement); ok {
+				return charLines, true
+			}
+		}
+		return f.placeholderGlyph()
+	}
+	return nil, false
+}
+
+// placeholderGlyph returns the glyph of the placeholder character
+func (f *Font) placeholderGlyph() ([]string, bool) {
+	charLines, exists := f.Glyphs[defaultPlaceholder]
+	return charLines, exists
+}
+
+// composeGlyph builds a single glyph from the glyphs of text placed side by
+// side, so that a transliteration like "EUR" still counts as one character
+func (f *Font) composeGlyph(text string) ([]string, bool) {
+	line := newGlyphLine(f.Height, Layout{Mode: LayoutFull})
+	for _, char := range text {
+		charLines, exists := f.Glyphs[char]
+		if !exists {
+			return nil, false
+		}
+		line.add(charLines, 0)
+	}
+
+	glyph := make([]string, f.Height)
+	for i, row := range line.rows {
+		glyph[i] = string(row)
+	}
+	return glyph, true
+}
@@ -0,0 +1,154 @@
+package ascii
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFallbackPolicies(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	plain := renderArt(t, "cafe", font, "", "", "")
+	placeholder := renderArt(t, "caf?", font, "", "", "")
+	skipped := renderArt(t, "caf", font, "", "", "")
+
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{FallbackSkip, skipped},
+		{"", skipped},
+		{FallbackPlaceholder, placeholder},
+		{FallbackTransliterate, plain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			got := renderArt(t, "café", font.WithFallback(tt.policy), "", "", "")
+			if got != tt.want {
+				t.Errorf("policy %q rendered:\n%s\nwant:\n%s", tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransliterateMultipleCharacters(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	got := renderArt(t, "€1", font.WithFallback(FallbackTransliterate), "", "", "")
+	want := renderArt(t, "EUR1", font, "", "", "")
+	if got != want {
+		t.Errorf("Transliterated euro sign rendered:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Greek text is romanised
+	got = renderArt(t, "Γειά", font.WithFallback(FallbackTransliterate), "", "", "")
+	want = renderArt(t, "Geia", font, "", "", "")
+	if got != want {
+		t.Errorf("Transliterated Greek rendered:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCheckCoverage(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		text         string
+		policy       string
+		wantChar     rune
+		wantPosition int
+	}{
+		{"all covered", "Hello", FallbackError, 0, 0},
+		{"accented letter", "café", FallbackError, 'é', 4},
+		{"after newline", "ab\\ncd€", FallbackError, '€', 7},
+		{"transliterated", "naïve", FallbackTransliterate, 0, 0},
+		{"no transliteration", "a→b", FallbackTransliterate, 0, 0},
+		{"skip policy", "naïve", FallbackSkip, 'ï', 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCoverage(tt.text, font.WithFallback(tt.policy))
+			if tt.wantChar == 0 {
+				if err != nil {
+					t.Errorf("CheckCoverage() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var missing *MissingGlyphError
+			if !errors.As(err, &missing) {
+				t.Fatalf("CheckCoverage() error = %v, want *MissingGlyphError", err)
+			}
+			if missing.Char != tt.wantChar || missing.Position != tt.wantPosition {
+				t.Errorf("CheckCoverage() = %q at %d, want %q at %d", missing.Char, missing.Position, tt.wantChar, tt.wantPosition)
+			}
+			if !strings.Contains(err.Error(), "U+") {
+				t.Errorf("Error message should name the code point: %v", err)
+			}
+		})
+	}
+}
+
+func TestBannerCodeTags(t *testing.T) {
+	standard, err := os.ReadFile("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("Failed to read banner: %v", err)
+	}
+
+	extra := "U+00E9\n" + strings.Repeat("é\n", 8) + "u+03a9\n" + strings.Repeat("Ω\n", 8)
+	path := filepath.Join(t.TempDir(), "extended.txt")
+	content := strings.TrimRight(string(standard), "\n") + "\n" + extra
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+
+	charMap, err := LoadBanner(path)
+	if err != nil {
+		t.Fatalf("LoadBanner() error = %v", err)
+	}
+
+	for _, char := range []rune{'é', 'Ω', '~', 'A'} {
+		if lines, exists := charMap[char]; !exists || len(lines) != 8 {
+			t.Errorf("Character %q missing or wrong height: %q", char, lines)
+		}
+	}
+	if charMap['é'][0] != "é" {
+		t.Errorf("Glyph for é = %q", charMap['é'])
+	}
+}
+
+func TestParseCodeTag(t *testing.T) {
+	tests := []struct {
+		separator string
+		want      rune
+		ok        bool
+	}{
+		{"U+00E9", 'é', true},
+		{"u+2500 ", '─', true},
+		{"", 0, false},
+		{"U+", 0, false},
+		{"U+XYZ", 0, false},
+		{"U+D800", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseCodeTag(tt.separator)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseCodeTag(%q) = %q, %v, want %q, %v", tt.separator, got, ok, tt.want, tt.ok)
+		}
+	}
+}
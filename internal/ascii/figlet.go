@@ -0,0 +1,206 @@
+package ascii
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// figletSignature is the magic prefix of every FIGlet 2 font header
+const figletSignature = "flf2a"
+
+// figletDeutsch lists the extra characters that follow the printable ASCII
+// block in every FIGlet font, in file order
+var figletDeutsch = []rune{196, 214, 220, 228, 246, 252, 223}
+
+// figletHeader holds the values declared on the first line of a .flf file
+type figletHeader struct {
+	Hardblank      rune
+	Height         int
+	Baseline       int
+	MaxLength      int
+	OldLayout      int
+	CommentLines   int
+	PrintDirection int
+	FullLayout     int
+	CodetagCount   int
+	hasFullLayout  bool
+}
+
+// isFIGlet reports whether the banner lines start with a FIGlet font header
+func isFIGlet(lines []string) bool {
+	return len(lines) > 0 && strings.HasPrefix(lines[0], figletSignature)
+}
+
+// parseFIGletHeader parses a header line such as "flf2a$ 6 5 16 15 11 0 24463"
+func parseFIGletHeader(line string) (figletHeader, error) {
+	var h figletHeader
+
+	if !strings.HasPrefix(line, figletSignature) {
+		return h, fmt.Errorf("missing %q signature", figletSignature)
+	}
+
+	rest := line[len(figletSignature):]
+	hardblank, size := utf8.DecodeRuneInString(rest)
+	if hardblank == utf8.RuneError || hardblank == ' ' {
+		return h, fmt.Errorf("missing hardblank character")
+	}
+	h.Hardblank = hardblank
+
+	fields := strings.Fields(rest[size:])
+	if len(fields) < 5 {
+		return h, fmt.Errorf("header has %d numeric fields, want at least 5", len(fields))
+	}
+
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return h, fmt.Errorf("invalid header field %q", field)
+		}
+		values[i] = n
+	}
+
+	h.Height = values[0]
+	h.Baseline = values[1]
+	h.MaxLength = values[2]
+	h.OldLayout = values[3]
+	h.CommentLines = values[4]
+	if len(values) > 5 {
+		h.PrintDirection = values[5]
+	}
+	if len(values) > 6 {
+		h.FullLayout = values[6]
+		h.hasFullLayout = true
+	}
+	if len(values) > 7 {
+		h.CodetagCount = values[7]
+	}
+
+	if h.Height < 1 {
+		return h, fmt.Errorf("invalid height %d", h.Height)
+	}
+	if h.CommentLines < 0 {
+		return h, fmt.Errorf("invalid comment line count %d", h.CommentLines)
+	}
+
+	return h, nil
+}
+
+// parseFIGletLines converts the lines of a .flf file into a character map.
+// Glyph rows keep the font's hardblank character; callers that render at
+// full width replace it with a space.
+func parseFIGletLines(lines []string) (map[rune][]string, figletHeader, error) {
+	if len(lines) == 0 {
+		return nil, figletHeader{}, fmt.Errorf("empty FIGlet font")
+	}
+
+	header, err := parseFIGletHeader(lines[0])
+	if err != nil {
+		return nil, header, fmt.Errorf("invalid FIGlet header: %w", err)
+	}
+
+	charMap := make(map[rune][]string)
+	pos := 1 + header.CommentLines
+
+	// readGlyph consumes header.Height lines starting at pos
+	readGlyph := func() ([]string, bool) {
+		if pos+header.Height > len(lines) {
+			return nil, false
+		}
+		glyph := make([]string, header.Height)
+		for j := 0; j < header.Height; j++ {
+			glyph[j] = stripEndmarks(lines[pos+j])
+		}
+		pos += header.Height
+		return glyph, true
+	}
+
+	// Required characters: printable ASCII followed by the Deutsch set
+	required := make([]rune, 0, 95+len(figletDeutsch))
+	for char := rune(32); char <= 126; char++ {
+		required = append(required, char)
+	}
+	required = append(required, figletDeutsch...)
+
+	for _, char := range required {
+		glyph, ok := readGlyph()
+		if !ok {
+			// Many fonts omit the Deutsch characters, so only the ASCII
+			// block is mandatory.
+			if char <= 126 {
+				return nil, header, fmt.Errorf("FIGlet font ends before character %q", char)
+			}
+			return charMap, header, nil
+		}
+		charMap[char] = glyph
+	}
+
+	// Code-tagged characters: a tag line followed by header.Height rows
+	for pos < len(lines) {
+		tagLine := strings.TrimSpace(lines[pos])
+		if tagLine == "" {
+			pos++
+			continue
+		}
+
+		code, err := parseFIGletCode(tagLine)
+		if err != nil {
+			return nil, header, fmt.Errorf("line %d: %w", pos+1, err)
+		}
+		pos++
+
+		glyph, ok := readGlyph()
+		if !ok {
+			return nil, header, fmt.Errorf("line %d: FIGlet font ends inside character %d", pos, code)
+		}
+
+		// Negative codes are reserved for translation tables; they never
+		// correspond to an input rune.
+		if code >= 0 {
+			charMap[rune(code)] = glyph
+		}
+	}
+
+	return charMap, header, nil
+}
+
+// parseFIGletCode parses the character code at the start of a code tag line.
+// Codes may be decimal, hexadecimal (0x) or octal (leading 0), and negative.
+func parseFIGletCode(tagLine string) (int64, error) {
+	field := strings.Fields(tagLine)[0]
+	code, err := strconv.ParseInt(field, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid code tag %q", field)
+	}
+	if code == -1 {
+		return 0, fmt.Errorf("code tag -1 is not allowed")
+	}
+	return code, nil
+}
+
+// stripEndmarks removes trailing whitespace and the run of endmark characters
+// that terminates every glyph row
+func stripEndmarks(line string) string {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" {
+		return line
+	}
+	endmark, _ := utf8.DecodeLastRuneInString(line)
+	return strings.TrimRight(line, string(endmark))
+}
+
+// replaceHardblanks returns a copy of the character map with the hardblank
+// character rendered as a regular space
+func replaceHardblanks(charMap map[rune][]string, hardblank rune) map[rune][]string {
+	result := make(map[rune][]string, len(charMap))
+	for char, glyph := range charMap {
+		rows := make([]string, len(glyph))
+		for i, row := range glyph {
+			rows[i] = strings.ReplaceAll(row, string(hardblank), " ")
+		}
+		result[char] = rows
+	}
+	return result
+}
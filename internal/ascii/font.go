@@ -0,0 +1,112 @@
+package ascii
+
+import "sort"
+
+// Metrics of the classic banner files, which carry no header
+const (
+	defaultHeight   = 8
+	defaultBaseline = 6
+)
+
+// Font is a loaded banner: its glyphs together with the metrics needed to render them
+type Font struct {
+	Name     string            // banner name, e.g. "standard"
+	Height   int               // number of rows in every glyph
+	Baseline int               // number of rows from the top down to the baseline
+	Layout   Layout            // how adjacent glyphs are joined
+	Fallback string            // policy for characters without a glyph
+	Glyphs   map[rune][]string // glyph rows for every supported character
+}
+
+// NewFont creates a full-width font from a character map, taking its height
+// from the tallest glyph
+func NewFont(name string, glyphs map[rune][]string) *Font {
+	font := fontFromMap(glyphs)
+	font.Name = name
+	return font
+}
+
+// Glyph returns the rows used to render char, applying the font's fallback
+// policy when the font has no glyph for it
+func (f *Font) Glyph(char rune) ([]string, bool) {
+	return f.glyph(char)
+}
+
+// Has reports whether the font declares a glyph for char
+func (f *Font) Has(char rune) bool {
+	_, exists := f.Glyphs[char]
+	return exists
+}
+
+// GlyphWidth returns the width in columns of the glyph used to render char,
+// or 0 if the character cannot be rendered
+func (f *Font) GlyphWidth(char rune) int {
+	charLines, exists := f.glyph(char)
+	if !exists {
+		return 0
+	}
+	return glyphWidth(charLines)
+}
+
+// TextWidth returns the rendered width of a single line of text once its
+// glyphs are joined by the font's layout
+func (f *Font) TextWidth(text string) int {
+	line := newGlyphLine(f.Height, f.Layout)
+	for _, char := range text {
+		if charLines, exists := f.glyph(char); exists {
+			line.add(charLines, 0)
+		}
+	}
+	return line.width()
+}
+
+// Runes returns the characters the font declares glyphs for, in ascending order
+func (f *Font) Runes() []rune {
+	runes := make([]rune, 0, len(f.Glyphs))
+	for char := range f.Glyphs {
+		runes = append(runes, char)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
+
+// WithLayout returns a copy of the font that joins glyphs using the given layout mode
+func (f *Font) WithLayout(mode string) *Font {
+	font := *f
+	font.Layout = f.Layout.WithMode(mode)
+	return &font
+}
+
+// WithFallback returns a copy of the font that handles missing characters using the given policy
+func (f *Font) WithFallback(policy string) *Font {
+	font := *f
+	font.Fallback = policy
+	return &font
+}
+
+// fontFromMap wraps a bare character map in a full-width font whose height
+// is taken from its tallest glyph
+func fontFromMap(charMap map[rune][]string) *Font {
+	height := 0
+	for _, glyph := range charMap {
+		if len(glyph) > height {
+			height = len(glyph)
+		}
+	}
+	if height == 0 {
+		height = defaultHeight
+	}
+
+	baseline := defaultBaseline
+	if baseline > height {
+		baseline = height
+	}
+
+	return &Font{
+		Height:   height,
+		Baseline: baseline,
+		Layout:   defaultLayout,
+		Fallback: FallbackSkip,
+		Glyphs:   charMap,
+	}
+}
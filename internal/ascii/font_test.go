@@ -0,0 +1,123 @@
+package ascii
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestBanner writes a banner file with the given header whose glyphs are
+// height rows of the character repeated twice
+func writeTestBanner(t *testing.T, header string, height int) string {
+	t.Helper()
+
+	var b strings.Builder
+	if header != "" {
+		b.WriteString(header + "\n")
+	}
+	for char := rune(32); char <= 126; char++ {
+		b.WriteString("\n")
+		for row := 0; row < height; row++ {
+			b.WriteString(strings.Repeat(string(char), 2) + "\n")
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+	return path
+}
+
+func TestLoadFontDefaultMetrics(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	if font.Height != 8 || font.Baseline != 6 {
+		t.Errorf("Height/Baseline = %d/%d, want 8/6", font.Height, font.Baseline)
+	}
+	if font.Layout.Mode != LayoutFull {
+		t.Errorf("Layout.Mode = %q, want %q", font.Layout.Mode, LayoutFull)
+	}
+}
+
+func TestLoadFontHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		height       int
+		wantHeight   int
+		wantBaseline int
+	}{
+		{"four rows", "# height=4 baseline=3", 4, 4, 3},
+		{"twelve rows", "# height=12 baseline=10", 12, 12, 10},
+		{"baseline defaults to bottom", "# height=6", 6, 6, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font, err := LoadFont(writeTestBanner(t, tt.header, tt.height))
+			if err != nil {
+				t.Fatalf("LoadFont() error = %v", err)
+			}
+
+			if font.Height != tt.wantHeight || font.Baseline != tt.wantBaseline {
+				t.Errorf("Height/Baseline = %d/%d, want %d/%d", font.Height, font.Baseline, tt.wantHeight, tt.wantBaseline)
+			}
+			if glyph := font.Glyphs['~']; len(glyph) != tt.wantHeight || glyph[0] != "~~" {
+				t.Errorf("Glyph '~' = %q, want %d rows of \"~~\"", glyph, tt.wantHeight)
+			}
+
+			lines := strings.Split(renderArt(t, "Hi", font, "", "", ""), "\n")
+			if len(lines) != tt.wantHeight {
+				t.Errorf("renderArt(t, ) returned %d lines, want %d", len(lines), tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestLoadFontInvalidHeader(t *testing.T) {
+	headers := []string{"# height=abc", "# height=0", "# heigth=4", "# height=4 baseline=5", "# height=4 baseline=-1", "# height 4"}
+
+	for _, header := range headers {
+		if _, err := LoadFont(writeTestBanner(t, header, 4)); err == nil {
+			t.Errorf("LoadFont() with header %q expected error", header)
+		}
+	}
+}
+
+func TestVariableHeightAlignment(t *testing.T) {
+	font, err := LoadFont(writeTestBanner(t, "# height=4 baseline=3", 4))
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	for _, alignment := range []string{"left", "right", "center", "justify"} {
+		lines := strings.Split(renderArt(t, "Hi there", font, "", "red", alignment), "\n")
+		if len(lines) != 4 {
+			t.Errorf("align=%s returned %d lines, want 4", alignment, len(lines))
+		}
+	}
+}
+
+func TestFontFromMap(t *testing.T) {
+	charMap := map[rune][]string{
+		'a': {"a", "a", "a"},
+		'b': {"b", "b", "b", "b"},
+	}
+
+	font := fontFromMap(charMap)
+	if font.Height != 4 {
+		t.Errorf("Height = %d, want 4", font.Height)
+	}
+	if font.Baseline > font.Height {
+		t.Errorf("Baseline %d exceeds height %d", font.Baseline, font.Height)
+	}
+
+	if got := fontFromMap(map[rune][]string{}).Height; got != 8 {
+		t.Errorf("Height of empty map = %d, want 8", got)
+	}
+}
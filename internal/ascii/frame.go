@@ -0,0 +1,189 @@
+package ascii
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Vertical alignments placing text within a fixed canvas
+const (
+	VAlignTop    = "top"
+	VAlignMiddle = "middle"
+	VAlignBottom = "bottom"
+)
+
+// IsValidVAlign reports whether valign names a supported vertical alignment
+func IsValidVAlign(valign string) bool {
+	switch valign {
+	case VAlignTop, VAlignMiddle, VAlignBottom:
+		return true
+	}
+	return false
+}
+
+// CanvasSize is a fixed output size. The zero value sizes the output to its text.
+type CanvasSize struct {
+	Width, Height int // columns and rows
+}
+
+// IsZero reports whether no size is set
+func (c CanvasSize) IsZero() bool {
+	return c == CanvasSize{}
+}
+
+// ParseCanvasSize parses a size given as columns x rows, such as "80x24"
+func ParseCanvasSize(spec string) (CanvasSize, error) {
+	cols, rows, found := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), "x")
+	width, errWidth := strconv.Atoi(cols)
+	height, errHeight := strconv.Atoi(rows)
+	if !found || errWidth != nil || errHeight != nil || width <= 0 || height <= 0 {
+		return CanvasSize{}, fmt.Errorf("invalid canvas size %q: expected columns x rows such as 80x24", spec)
+	}
+	return CanvasSize{Width: width, Height: height}, nil
+}
+
+// Spacing is the blank space on each side of a block of text, in columns
+// to the left and right and rows above and below
+type Spacing struct {
+	Top, Right, Bottom, Left int
+}
+
+// ParseSpacing parses a spacing given like a CSS margin: "2" for every side,
+// "1,2" for top and bottom then left and right, or "1,2,3,4" for the top,
+// right, bottom and left sides
+func ParseSpacing(spec string) (Spacing, error) {
+	var values []int
+	for _, item := range strings.Split(spec, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || value < 0 {
+			return Spacing{}, fmt.Errorf("invalid spacing %q: expected 1, 2 or 4 comma-separated non-negative numbers", spec)
+		}
+		values = append(values, value)
+	}
+
+	switch len(values) {
+	case 1:
+		return Spacing{values[0], values[0], values[0], values[0]}, nil
+	case 2:
+		return Spacing{values[0], values[1], values[0], values[1]}, nil
+	case 4:
+		return Spacing{values[0], values[1], values[2], values[3]}, nil
+	}
+	return Spacing{}, fmt.Errorf("invalid spacing %q: expected 1, 2 or 4 comma-separated non-negative numbers", spec)
+}
+
+// horizontal returns the columns taken on the left and right sides
+func (s Spacing) horizontal() int {
+	return s.Left + s.Right
+}
+
+// vertical returns the rows taken above and below
+func (s Spacing) vertical() int {
+	return s.Top + s.Bottom
+}
+
+// isFramed reports whether the rendered rows are placed on a canvas with a
+// fixed size, padding or margins
+func (r *Renderer) isFramed() bool {
+	return !r.options.Canvas.IsZero() || r.options.Padding != Spacing{} || r.options.Margin != Spacing{}
+}
+
+// canvasColumns returns the number of columns of a fixed canvas available to
+// cells: in dollar mode the last column is taken by the $ terminator
+func (r *Renderer) canvasColumns() int {
+	if r.options.Output == OutputDollar {
+		return r.options.Canvas.Width - 1
+	}
+	return r.options.Canvas.Width
+}
+
+// textWidth returns the width of the area text is wrapped and aligned in
+// on a fixed canvas, once margins and padding are taken away, counting the
+// column the aligners reserve for the $ terminator
+func (r *Renderer) textWidth() int {
+	return r.canvasColumns() - r.options.Margin.horizontal() - r.options.Padding.horizontal() + 1
+}
+
+// frame places rows of cells within the padding and margins and, on a fixed
+// canvas, aligns them vertically, cropping what does not fit. Every row of
+// the result has the same width. With a background for the whole text, the
+// padding and the blank cells around the text take the background too.
+func (r *Renderer) frame(rows [][]Cell) [][]Cell {
+	padding, margin := r.options.Padding, r.options.Margin
+
+	// Size of the text area
+	width, height := 0, len(rows)
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	if !r.options.Canvas.IsZero() {
+		width = r.textWidth() - 1
+		height = r.options.Canvas.Height - margin.vertical() - padding.vertical()
+	}
+
+	// Vertical alignment: blank rows above, or the rows cropped from the top
+	top := 0
+	switch r.options.VAlign {
+	case VAlignMiddle:
+		top = (height - len(rows)) / 2
+	case VAlignBottom:
+		top = height - len(rows)
+	}
+	if top < 0 {
+		rows = rows[-top:]
+		top = 0
+	}
+
+	fill := Cell{Rune: ' ', Source: -1}
+	if r.options.Substring == "" {
+		fill.Style.Background = r.style.Background
+	}
+	fillRow := func(n int) []Cell {
+		cells := make([]Cell, n)
+		for i := range cells {
+			cells[i] = fill
+		}
+		return cells
+	}
+
+	boxWidth := padding.Left + width + padding.Right
+	var box [][]Cell
+	for i := 0; i < padding.Top+top; i++ {
+		box = append(box, fillRow(boxWidth))
+	}
+	for _, row := range rows {
+		if len(box) == padding.Top+height {
+			break
+		}
+		if len(row) > width {
+			row = row[:width]
+		}
+		cells := fillRow(padding.Left)
+		for _, cell := range row {
+			if cell.Source < 0 {
+				cell.Style.Background = fill.Style.Background
+			}
+			cells = append(cells, cell)
+		}
+		box = append(box, append(cells, fillRow(width-len(row)+padding.Right)...))
+	}
+	for len(box) < padding.Top+height+padding.Bottom {
+		box = append(box, fillRow(boxWidth))
+	}
+
+	// Margins stay blank
+	framed := make([][]Cell, 0, margin.Top+len(box)+margin.Bottom)
+	for i := 0; i < margin.Top; i++ {
+		framed = append(framed, blankCells(margin.Left+boxWidth+margin.Right))
+	}
+	for _, row := range box {
+		framed = append(framed, append(append(blankCells(margin.Left), row...), blankCells(margin.Right)...))
+	}
+	for i := 0; i < margin.Bottom; i++ {
+		framed = append(framed, blankCells(margin.Left+boxWidth+margin.Right))
+	}
+	return framed
+}
@@ -0,0 +1,165 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCanvasSize(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    CanvasSize
+		wantErr bool
+	}{
+		{"80x24", CanvasSize{80, 24}, false},
+		{" 120X40 ", CanvasSize{120, 40}, false},
+		{"80", CanvasSize{}, true},
+		{"0x24", CanvasSize{}, true},
+		{"80x-1", CanvasSize{}, true},
+		{"wide x tall", CanvasSize{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseCanvasSize(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCanvasSize(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCanvasSize(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSpacing(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Spacing
+		wantErr bool
+	}{
+		{"2", Spacing{2, 2, 2, 2}, false},
+		{"1,3", Spacing{1, 3, 1, 3}, false},
+		{"1, 2, 3, 4", Spacing{1, 2, 3, 4}, false},
+		{"0", Spacing{}, false},
+		{"1,2,3", Spacing{}, true},
+		{"-1", Spacing{}, true},
+		{"", Spacing{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSpacing(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSpacing(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSpacing(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRendererCanvasValidation(t *testing.T) {
+	font := narrowFont("ab")
+
+	tests := []struct {
+		name    string
+		options Options
+		wantErr bool
+	}{
+		{"canvas", Options{Canvas: CanvasSize{80, 24}, VAlign: VAlignMiddle, Padding: Spacing{1, 2, 1, 2}, Margin: Spacing{1, 1, 1, 1}}, false},
+		{"padding without a canvas", Options{Padding: Spacing{1, 1, 1, 1}}, false},
+		{"invalid vertical alignment", Options{VAlign: "center"}, true},
+		{"negative padding", Options{Padding: Spacing{Left: -1}}, true},
+		{"width and canvas", Options{Width: 80, Canvas: CanvasSize{80, 24}}, true},
+		{"trimmed canvas", Options{Canvas: CanvasSize{30, 10}, Output: OutputTrim}, true},
+		{"trimmed padding without a canvas", Options{Padding: Spacing{1, 1, 1, 1}, Output: OutputTrim}, false},
+		{"negative canvas", Options{Canvas: CanvasSize{-80, 24}}, true},
+		{"no room for text", Options{Canvas: CanvasSize{10, 4}, Padding: Spacing{2, 5, 2, 5}}, true},
+		{"no room beside the $ column", Options{Canvas: CanvasSize{3, 1}, Margin: Spacing{Left: 2}, Output: OutputDollar}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRenderer(font, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRenderer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRendererCanvas(t *testing.T) {
+	font := narrowFont("abcd ")
+
+	tests := []struct {
+		name    string
+		text    string
+		options Options
+		want    []string
+	}{
+		{"top", "ab", Options{Canvas: CanvasSize{6, 3}}, []string{"ab    ", "      ", "      "}},
+		{"middle", "ab", Options{Canvas: CanvasSize{6, 3}, VAlign: VAlignMiddle}, []string{"      ", "ab    ", "      "}},
+		{"bottom", "ab", Options{Canvas: CanvasSize{6, 3}, VAlign: VAlignBottom}, []string{"      ", "      ", "ab    "}},
+		{"aligns within the canvas", "ab", Options{Canvas: CanvasSize{6, 1}, Alignment: "right"}, []string{"    ab"}},
+		{"dollar mode takes the last column", "ab", Options{Canvas: CanvasSize{6, 2}, Output: OutputDollar}, []string{"ab   $", "     $"}},
+		{"crops long text", "abcdabcd", Options{Canvas: CanvasSize{5, 1}}, []string{"abcda"}},
+		{"wraps within the canvas", "aaaa bbbb cccc", Options{Canvas: CanvasSize{13, 2}, Wrap: WrapGreedy}, []string{"aaaa bbbb    ", "cccc         "}},
+		{"crops rows below", "aaaa bbbb cccc dddd", Options{Canvas: CanvasSize{13, 1}}, []string{"aaaa bbbb    "}},
+		{"crops rows above at the bottom", "aaaa bbbb cccc dddd", Options{Canvas: CanvasSize{13, 1}, VAlign: VAlignBottom}, []string{"cccc dddd    "}},
+		{"padding and margins", "ab", Options{Canvas: CanvasSize{8, 5}, Padding: Spacing{1, 1, 1, 1}, Margin: Spacing{1, 1, 1, 1}}, []string{"        ", "        ", "  ab    ", "        ", "        "}},
+		{"padding without a canvas", "ab", Options{Padding: Spacing{0, 2, 1, 1}}, []string{" ab  ", "     "}},
+		{"empty text keeps the canvas", "", Options{Canvas: CanvasSize{3, 2}}, []string{"   ", "   "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := NewRenderer(font, tt.options)
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+			result, err := renderer.Render(tt.text)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			got := result.Encode(PlainEncoder{})
+			if want := strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("Render(%q) =\n%q\nwant\n%q", tt.text, got, want)
+			}
+		})
+	}
+}
+
+func TestRendererCanvasBackground(t *testing.T) {
+	renderer, err := NewRenderer(narrowFont("ab"), Options{
+		Canvas:     CanvasSize{6, 4},
+		Padding:    Spacing{1, 1, 1, 1},
+		Margin:     Spacing{Left: 1},
+		Background: "blue",
+	})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	result, err := renderer.Render("ab")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	blue, _ := ParseColor("blue")
+	for i, row := range result.Canvas.Rows {
+		if len(row) != 6 {
+			t.Fatalf("row %d has %d cells, want 6", i, len(row))
+		}
+		for j, cell := range row {
+			want := blue
+			if j == 0 {
+				want = Color{} // the margin stays blank
+			}
+			if cell.Style.Background != want {
+				t.Errorf("cell (%d, %d) background = %+v, want %+v", i, j, cell.Style.Background, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package ascii
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+)
+
+// Color spaces a gradient can be interpolated in
+const (
+	InterpolateRGB        = "rgb"        // blend the sRGB components
+	InterpolatePerceptual = "perceptual" // blend in the OKLab space, keeping lightness even
+)
+
+// IsValidInterpolation reports whether space names a supported interpolation space
+func IsValidInterpolation(space string) bool {
+	switch space {
+	case InterpolateRGB, InterpolatePerceptual:
+		return true
+	}
+	return false
+}
+
+// cellAspect is the height of a character cell relative to its width. It
+// keeps diagonal gradients at the requested angle on screen.
+const cellAspect = 2.0
+
+// Gradient blends evenly spaced color stops across the rendered output
+type Gradient struct {
+	Stops []Color // at least two colors, from the start to the end of the gradient
+	Angle float64 // direction in degrees: 0 runs left to right, 90 top to bottom
+	Space string  // InterpolateRGB or InterpolatePerceptual; empty for RGB
+}
+
+// ParseGradient parses a comma-separated list of at least two color stops,
+// each in any notation ParseColor accepts, such as "#ff0000,#0000ff"
+func ParseGradient(spec string) ([]Color, error) {
+	var stops []Color
+	for _, item := range splitColorList(spec) {
+		color, err := ParseColor(item)
+		if err != nil {
+			return nil, err
+		}
+		stops = append(stops, color)
+	}
+	if len(stops) < 2 {
+		return nil, fmt.Errorf("%w %q: a gradient needs at least two colors", ErrInvalidColor, spec)
+	}
+	return stops, nil
+}
+
+// splitColorList splits a comma-separated list of colors, leaving the commas
+// inside rgb() and hsl() alone
+func splitColorList(spec string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, char := range spec {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, spec[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(items, spec[start:])
+}
+
+// At returns the color at position t, from 0 at the first stop to 1 at the last
+func (g *Gradient) At(t float64) Color {
+	t = math.Max(0, math.Min(1, t))
+	segment := t * float64(len(g.Stops)-1)
+	i := int(segment)
+	if i >= len(g.Stops)-1 {
+		i = len(g.Stops) - 2
+	}
+	return mixColors(g.Stops[i], g.Stops[i+1], segment-float64(i), g.Space)
+}
+
+// apply colors the cells of the canvas produced by selected source runes,
+// spreading the gradient over the bounding box of those cells. Whitespace
+// cells are left alone, since a foreground color would not show on them.
+func (g *Gradient) apply(canvas *Canvas, selected []bool) {
+	isSelected := func(cell Cell) bool {
+		return cell.Source >= 0 && cell.Source < len(selected) && selected[cell.Source]
+	}
+
+	minX, minY, maxX, maxY := -1, -1, -1, -1
+	for y, row := range canvas.Rows {
+		for x, cell := range row {
+			if !isSelected(cell) {
+				continue
+			}
+			if minX < 0 || x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if minY < 0 {
+				minY = y
+			}
+			maxY = y
+		}
+	}
+	if minX < 0 {
+		return
+	}
+
+	// Project every position onto the gradient direction and scale the
+	// projections of the bounding box corners to 0..1
+	angle := g.Angle * math.Pi / 180
+	dx, dy := math.Cos(angle), math.Sin(angle)*cellAspect
+	project := func(x, y int) float64 {
+		return float64(x)*dx + float64(y)*dy
+	}
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, corner := range [][2]int{{minX, minY}, {maxX, minY}, {minX, maxY}, {maxX, maxY}} {
+		p := project(corner[0], corner[1])
+		low, high = math.Min(low, p), math.Max(high, p)
+	}
+
+	for y, row := range canvas.Rows {
+		for x := range row {
+			if !isSelected(row[x]) || unicode.IsSpace(row[x].Rune) {
+				continue
+			}
+			t := 0.0
+			if high > low {
+				t = (project(x, y) - low) / (high - low)
+			}
+			row[x].Style.Foreground = g.At(t)
+		}
+	}
+}
+
+// mixColors blends from a to b by t in the given space
+func mixColors(a, b Color, t float64, space string) Color {
+	ar, ag, ab := a.RGB()
+	br, bg, bb := b.RGB()
+
+	if space == InterpolatePerceptual {
+		l1, m1, s1 := rgbToOKLab(ar, ag, ab)
+		l2, m2, s2 := rgbToOKLab(br, bg, bb)
+		r, g, b := okLabToRGB(lerp(l1, l2, t), lerp(m1, m2, t), lerp(s1, s2, t))
+		return RGBColor(r, g, b)
+	}
+
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(lerp(float64(x), float64(y), t)))
+	}
+	return RGBColor(mix(ar, br), mix(ag, bg), mix(ab, bb))
+}
+
+// lerp interpolates linearly from a to b
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// rgbToOKLab converts an sRGB color to OKLab lightness and a, b components
+func rgbToOKLab(r, g, b uint8) (float64, float64, float64) {
+	lr, lg, lb := srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+
+	l := math.Cbrt(0.4122214708*lr + 0.5363325363*lg + 0.0514459929*lb)
+	m := math.Cbrt(0.2119034982*lr + 0.6806995451*lg + 0.1073969566*lb)
+	s := math.Cbrt(0.0883024619*lr + 0.2817188376*lg + 0.6299787005*lb)
+
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+// okLabToRGB converts an OKLab color back to sRGB, clamping out-of-gamut values
+func okLabToRGB(lightness, a, b float64) (uint8, uint8, uint8) {
+	l := lightness + 0.3963377774*a + 0.2158037573*b
+	m := lightness - 0.1055613458*a - 0.0638541728*b
+	s := lightness - 0.0894841775*a - 1.2914855480*b
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	return linearToSRGB(4.0767416621*l - 3.3077115913*m + 0.2309699292*s),
+		linearToSRGB(-1.2684380046*l + 2.6097574011*m - 0.3413193965*s),
+		linearToSRGB(-0.0041960863*l - 0.7034186147*m + 1.7076147010*s)
+}
+
+// srgbToLinear converts an sRGB component to linear light from 0 to 1
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts linear light to an sRGB component
+func linearToSRGB(c float64) uint8 {
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(math.Max(0, math.Min(1, c)) * 255))
+}
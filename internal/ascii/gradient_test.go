@@ -0,0 +1,170 @@
+package ascii
+
+import (
+	"errors"
+	"testing"
+
+	"ascii-art/assets"
+)
+
+func TestParseGradient(t *testing.T) {
+	stops, err := ParseGradient("#ff0000, rgb(0, 255, 0),blue")
+	if err != nil {
+		t.Fatalf("ParseGradient() error = %v", err)
+	}
+	want := []Color{RGBColor(255, 0, 0), RGBColor(0, 255, 0), {Type: ColorBasic, Index: 4}}
+	if len(stops) != len(want) {
+		t.Fatalf("ParseGradient() = %+v, want %+v", stops, want)
+	}
+	for i := range want {
+		if stops[i] != want[i] {
+			t.Errorf("stop %d = %+v, want %+v", i, stops[i], want[i])
+		}
+	}
+
+	for _, spec := range []string{"", "#ff0000", "#ff0000,nope"} {
+		if _, err := ParseGradient(spec); !errors.Is(err, ErrInvalidColor) {
+			t.Errorf("ParseGradient(%q) error = %v, want %v", spec, err, ErrInvalidColor)
+		}
+	}
+}
+
+func TestGradientAt(t *testing.T) {
+	red, blue := RGBColor(255, 0, 0), RGBColor(0, 0, 255)
+
+	tests := []struct {
+		name     string
+		gradient Gradient
+		t        float64
+		want     Color
+	}{
+		{"start", Gradient{Stops: []Color{red, blue}}, 0, red},
+		{"end", Gradient{Stops: []Color{red, blue}}, 1, blue},
+		{"rgb midpoint", Gradient{Stops: []Color{red, blue}}, 0.5, RGBColor(128, 0, 128)},
+		{"perceptual midpoint", Gradient{Stops: []Color{red, blue}, Space: InterpolatePerceptual}, 0.5, RGBColor(140, 83, 162)},
+		{"perceptual end", Gradient{Stops: []Color{red, blue}, Space: InterpolatePerceptual}, 1, blue},
+		{"middle stop", Gradient{Stops: []Color{red, RGBColor(0, 255, 0), blue}}, 0.5, RGBColor(0, 255, 0)},
+		{"clamped", Gradient{Stops: []Color{red, blue}}, 2, blue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.gradient.At(tt.t); got != tt.want {
+				t.Errorf("At(%v) = %+v, want %+v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderGradient(t *testing.T) {
+	font, err := LoadFontFS(assets.Banners, "standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFontFS() error = %v", err)
+	}
+	red := RGBColor(255, 0, 0)
+
+	// firstAndLast returns the colors of the first and last colored cells in reading order
+	firstAndLast := func(canvas *Canvas) (first, last Color) {
+		for _, row := range canvas.Rows {
+			for _, cell := range row {
+				if cell.Style.Foreground.IsDefault() {
+					continue
+				}
+				if first.IsDefault() {
+					first = cell.Style.Foreground
+				}
+				last = cell.Style.Foreground
+			}
+		}
+		return first, last
+	}
+
+	t.Run("horizontal", func(t *testing.T) {
+		renderer, err := NewRenderer(font, Options{Gradient: "#ff0000,#0000ff", Width: 200})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render("Hi")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+
+		// Every column has a single color, red at the left edge and blue at the right
+		for x := 0; x < result.Canvas.Width(); x++ {
+			var column Color
+			for _, row := range result.Canvas.Rows {
+				if x >= len(row) || row[x].Style.Foreground.IsDefault() {
+					continue
+				}
+				if !column.IsDefault() && row[x].Style.Foreground != column {
+					t.Fatalf("column %d mixes %+v and %+v", x, column, row[x].Style.Foreground)
+				}
+				column = row[x].Style.Foreground
+			}
+			if x == 0 && column != red {
+				t.Errorf("first column = %+v, want %+v", column, red)
+			}
+		}
+	})
+
+	t.Run("vertical", func(t *testing.T) {
+		renderer, err := NewRenderer(font, Options{Gradient: "#ff0000,#0000ff", GradientAngle: 90, Width: 200})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render("Hi")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+
+		first, last := firstAndLast(result.Canvas)
+		if first != red {
+			t.Errorf("top color = %+v, want %+v", first, red)
+		}
+		if last == red || last == first {
+			t.Errorf("bottom color = %+v, want a color towards blue", last)
+		}
+		for _, cell := range result.Canvas.Rows[0] {
+			if !cell.Style.Foreground.IsDefault() && cell.Style.Foreground != red {
+				t.Errorf("top row cell %q = %+v, want %+v", cell.Rune, cell.Style.Foreground, red)
+			}
+		}
+	})
+
+	t.Run("substring only", func(t *testing.T) {
+		renderer, err := NewRenderer(font, Options{Gradient: "#ff0000,#0000ff", Substring: "b", Width: 200})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render("abc")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+
+		for _, row := range result.Canvas.Rows {
+			for _, cell := range row {
+				if !cell.Style.Foreground.IsDefault() && cell.Source != 1 {
+					t.Fatalf("cell %+v outside the substring is colored", cell)
+				}
+			}
+		}
+		// The gradient spans the substring, not the whole banner
+		first, last := firstAndLast(result.Canvas)
+		if first.R <= last.R || first.B >= last.B {
+			t.Errorf("substring gradient runs from %+v to %+v, want red towards blue", first, last)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		tests := []Options{
+			{Gradient: "#ff0000,#0000ff", Color: "red"},
+			{Gradient: "#ff0000"},
+			{Gradient: "#ff0000,#0000ff", GradientSpace: "hsv"},
+		}
+		for _, options := range tests {
+			if _, err := NewRenderer(font, options); err == nil {
+				t.Errorf("NewRenderer(%+v) should fail", options)
+			}
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package ascii
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Horizontal layout modes
+const (
+	LayoutFull  = "full"  // glyphs are placed side by side at their full width
+	LayoutFit   = "fit"   // glyphs are moved together until they touch (kerning)
+	LayoutSmush = "smush" // glyphs overlap by one column where the smushing rules allow
+)
+
+// FIGlet horizontal smushing rule bits
+const (
+	SmushEqual      = 1  // rule 1: two identical characters become one
+	SmushUnderscore = 2  // rule 2: an underscore is replaced by a border character
+	SmushHierarchy  = 4  // rule 3: the character of the higher class wins
+	SmushPair       = 8  // rule 4: opposite brackets become a vertical bar
+	SmushBigX       = 16 // rule 5: "/\" becomes "|", "\/" becomes "Y" and "><" becomes "X"
+	SmushHardblank  = 32 // rule 6: two hardblanks become one
+)
+
+// FIGlet full_layout bits selecting the horizontal mode
+const (
+	figletHorizontalFit   = 64
+	figletHorizontalSmush = 128
+)
+
+// Layout controls how adjacent glyphs are joined horizontally
+type Layout struct {
+	Mode      string // LayoutFull, LayoutFit or LayoutSmush
+	Rules     int    // smushing rule bits; 0 means universal smushing
+	Hardblank rune   // renders as a space but is never fitted away; 0 if unused
+}
+
+// defaultLayout renders glyphs at full width, as the classic banners expect
+var defaultLayout = Layout{Mode: LayoutFull}
+
+// IsValidLayout reports whether mode names a supported layout mode
+func IsValidLayout(mode string) bool {
+	switch mode {
+	case LayoutFull, LayoutFit, LayoutSmush:
+		return true
+	}
+	return false
+}
+
+// WithMode returns a copy of the layout using the given mode, keeping the
+// font's smushing rules and hardblank
+func (l Layout) WithMode(mode string) Layout {
+	l.Mode = mode
+	return l
+}
+
+// layout returns the layout declared by a FIGlet header. The full_layout field
+// takes precedence over the older old_layout field when present.
+func (h figletHeader) layout() Layout {
+	layout := Layout{Mode: LayoutFull, Hardblank: h.Hardblank}
+
+	if h.hasFullLayout {
+		layout.Rules = h.FullLayout & 63
+		switch {
+		case h.FullLayout&figletHorizontalSmush != 0:
+			layout.Mode = LayoutSmush
+		case h.FullLayout&figletHorizontalFit != 0:
+			layout.Mode = LayoutFit
+		}
+		return layout
+	}
+
+	switch {
+	case h.OldLayout == 0:
+		layout.Mode = LayoutFit
+	case h.OldLayout > 0:
+		layout.Mode = LayoutSmush
+		layout.Rules = h.OldLayout & 63
+	}
+	return layout
+}
+
+// glyphLine accumulates glyphs into rows of runes using a layout. For every
+// output column it records which glyph produced it, so coloring can be
+// decided after glyphs have been fitted or smushed together.
+type glyphLine struct {
+	layout    Layout
+	rows      [][]rune
+	owners    [][]int
+	lastWidth int // width of the previously added glyph
+}
+
+// newGlyphLine creates an empty line with the given number of rows
+func newGlyphLine(height int, layout Layout) *glyphLine {
+	return &glyphLine{
+		layout: layout,
+		rows:   make([][]rune, height),
+		owners: make([][]int, height),
+	}
+}
+
+// width returns the current width of the line in columns
+func (l *glyphLine) width() int {
+	if len(l.rows) == 0 {
+		return 0
+	}
+	return len(l.rows[0])
+}
+
+// widthWith returns the width the line would have after adding glyph
+func (l *glyphLine) widthWith(glyph []string) int {
+	return l.width() + glyphWidth(glyph) - l.overlap(glyph)
+}
+
+// add appends glyph to the line, attributing its columns to owner
+func (l *glyphLine) add(glyph []string, owner int) {
+	gw := glyphWidth(glyph)
+	amount := l.overlap(glyph)
+	start := l.width() - amount
+
+	for row := range l.rows {
+		chars := padRunes(glyphRow(glyph, row), gw)
+		for k, ch := range chars {
+			column := start + k
+			if column < 0 {
+				// Only blank leading columns can fall before the line start
+				continue
+			}
+			if column < len(l.rows[row]) {
+				// Overlapping column: a blank on the line side always yields
+				// to the glyph, otherwise the smushing rules decide
+				if merged := l.smush(l.rows[row][column], ch, gw); merged != 0 {
+					l.rows[row][column] = merged
+					if ch != ' ' {
+						l.owners[row][column] = owner
+					}
+				}
+				continue
+			}
+			l.rows[row] = append(l.rows[row], ch)
+			l.owners[row] = append(l.owners[row], owner)
+		}
+	}
+
+	l.lastWidth = gw
+}
+
+// overlap returns how many columns glyph can be moved into the line, following
+// the FIGlet kerning and smushing algorithm
+func (l *glyphLine) overlap(glyph []string) int {
+	if l.layout.Mode != LayoutFit && l.layout.Mode != LayoutSmush {
+		return 0
+	}
+	if l.width() == 0 {
+		return 0
+	}
+
+	gw := glyphWidth(glyph)
+	maximum := gw
+	for row := range l.rows {
+		line := l.rows[row]
+		chars := padRunes(glyphRow(glyph, row), gw)
+
+		// Rightmost non-blank column of the line
+		lineEnd := len(line) - 1
+		for lineEnd > 0 && line[lineEnd] == ' ' {
+			lineEnd--
+		}
+		// Leftmost non-blank column of the glyph
+		charStart := 0
+		for charStart < len(chars) && chars[charStart] == ' ' {
+			charStart++
+		}
+
+		amount := charStart + len(line) - 1 - lineEnd
+		left := line[lineEnd]
+		if left == ' ' {
+			amount++
+		} else if charStart < len(chars) && l.smush(left, chars[charStart], gw) != 0 {
+			amount++
+		}
+
+		if amount < maximum {
+			maximum = amount
+		}
+	}
+
+	if maximum < 0 {
+		return 0
+	}
+	return maximum
+}
+
+// smush returns the character produced by overlapping left and right, or 0 if
+// the two cannot share a column
+func (l *glyphLine) smush(left, right rune, rightWidth int) rune {
+	if left == ' ' {
+		return right
+	}
+	if right == ' ' {
+		return left
+	}
+
+	// Single-column glyphs never smush, matching FIGlet
+	if l.lastWidth < 2 || rightWidth < 2 {
+		return 0
+	}
+	if l.layout.Mode != LayoutSmush {
+		return 0
+	}
+
+	hardblank := l.layout.Hardblank
+	rules := l.layout.Rules
+
+	if rules == 0 {
+		// Universal smushing: the later character wins, except over hardblanks
+		if left == hardblank && hardblank != 0 {
+			return right
+		}
+		if right == hardblank && hardblank != 0 {
+			return left
+		}
+		return right
+	}
+
+	if hardblank != 0 {
+		if rules&SmushHardblank != 0 && left == hardblank && right == hardblank {
+			return left
+		}
+		if left == hardblank || right == hardblank {
+			return 0
+		}
+	}
+
+	if rules&SmushEqual != 0 && left == right {
+		return left
+	}
+
+	if rules&SmushUnderscore != 0 {
+		const borders = "|/\\[]{}()<>"
+		if left == '_' && strings.ContainsRune(borders, right) {
+			return right
+		}
+		if right == '_' && strings.ContainsRune(borders, left) {
+			return left
+		}
+	}
+
+	if rules&SmushHierarchy != 0 {
+		classes := []string{"|", "/\\", "[]", "{}", "()", "<>"}
+		leftClass, rightClass := -1, -1
+		for i, class := range classes {
+			if strings.ContainsRune(class, left) {
+				leftClass = i
+			}
+			if strings.ContainsRune(class, right) {
+				rightClass = i
+			}
+		}
+		if leftClass >= 0 && rightClass >= 0 && leftClass != rightClass {
+			if leftClass > rightClass {
+				return left
+			}
+			return right
+		}
+	}
+
+	if rules&SmushPair != 0 {
+		switch string([]rune{left, right}) {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|'
+		}
+	}
+
+	if rules&SmushBigX != 0 {
+		switch string([]rune{left, right}) {
+		case "/\\":
+			return '|'
+		case "\\/":
+			return 'Y'
+		case "><":
+			return 'X'
+		}
+	}
+
+	return 0
+}
+
+// glyphWidth returns the width of a glyph in columns (its widest row)
+func glyphWidth(glyph []string) int {
+	width := 0
+	for _, row := range glyph {
+		if w := utf8.RuneCountInString(row); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// glyphRow returns a row of the glyph, or an empty row past its height
+func glyphRow(glyph []string, row int) string {
+	if row < len(glyph) {
+		return glyph[row]
+	}
+	return ""
+}
+
+// padRunes converts s to runes padded with spaces to width
+func padRunes(s string, width int) []rune {
+	chars := []rune(s)
+	for len(chars) < width {
+		chars = append(chars, ' ')
+	}
+	return chars
+}
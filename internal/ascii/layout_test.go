@@ -0,0 +1,173 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFIGletHeaderLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		header figletHeader
+		want   Layout
+	}{
+		{"old layout full width", figletHeader{OldLayout: -1}, Layout{Mode: LayoutFull}},
+		{"old layout fitting", figletHeader{OldLayout: 0}, Layout{Mode: LayoutFit}},
+		{"old layout smushing", figletHeader{OldLayout: 15}, Layout{Mode: LayoutSmush, Rules: 15}},
+		{"full layout smushing", figletHeader{OldLayout: -1, FullLayout: 128 + 24, hasFullLayout: true}, Layout{Mode: LayoutSmush, Rules: 24}},
+		{"full layout fitting", figletHeader{OldLayout: 15, FullLayout: 64 + 15, hasFullLayout: true}, Layout{Mode: LayoutFit, Rules: 15}},
+		{"full layout full width", figletHeader{OldLayout: 15, FullLayout: 0, hasFullLayout: true}, Layout{Mode: LayoutFull}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.header.layout(); got != tt.want {
+				t.Errorf("layout() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmushRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       int
+		left, right rune
+		want        rune
+	}{
+		{"equal character", SmushEqual, '|', '|', '|'},
+		{"equal disabled", SmushUnderscore, '|', '|', 0},
+		{"underscore left", SmushUnderscore, '_', '/', '/'},
+		{"underscore right", SmushUnderscore, '[', '_', '['},
+		{"hierarchy bar and slash", SmushHierarchy, '|', '/', '/'},
+		{"hierarchy bracket and brace", SmushHierarchy, '{', ']', '{'},
+		{"hierarchy same class", SmushHierarchy, '/', '\\', 0},
+		{"opposite pair brackets", SmushPair, '[', ']', '|'},
+		{"opposite pair parens", SmushPair, ')', '(', '|'},
+		{"big X bar", SmushBigX, '/', '\\', '|'},
+		{"big X Y", SmushBigX, '\\', '/', 'Y'},
+		{"big X X", SmushBigX, '>', '<', 'X'},
+		{"hardblank pair", SmushHardblank, '$', '$', '$'},
+		{"hardblank blocks smushing", SmushEqual, '$', '$', 0},
+		{"no matching rule", SmushEqual | SmushPair, 'a', 'b', 0},
+		{"universal smushing", 0, 'a', 'b', 'b'},
+		{"universal keeps letter over hardblank", 0, 'a', '$', 'a'},
+		{"blank yields", SmushEqual, ' ', 'x', 'x'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := newGlyphLine(1, Layout{Mode: LayoutSmush, Rules: tt.rules, Hardblank: '$'})
+			line.lastWidth = 2
+			if got := line.smush(tt.left, tt.right, 2); got != tt.want {
+				t.Errorf("smush(%q, %q) = %q, want %q", tt.left, tt.right, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlyphLineModes(t *testing.T) {
+	left := []string{"|  ", "|  "}
+	right := []string{"  |", " _|"}
+
+	tests := []struct {
+		name  string
+		mode  string
+		rules int
+		want  []string
+	}{
+		{"full width", LayoutFull, 0, []string{"|    |", "|   _|"}},
+		{"fitting", LayoutFit, 0, []string{"| |", "|_|"}},
+		{"smushing without matching rule", LayoutSmush, SmushEqual, []string{"| |", "|_|"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := newGlyphLine(2, Layout{Mode: tt.mode, Rules: tt.rules})
+			line.add(left, 0)
+			wantWidth := len(tt.want[0])
+			if got := line.widthWith(right); got != wantWidth {
+				t.Errorf("widthWith() = %d, want %d", got, wantWidth)
+			}
+			line.add(right, 1)
+
+			for i, want := range tt.want {
+				if got := string(line.rows[i]); got != want {
+					t.Errorf("row %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGlyphLineSmushOverlap(t *testing.T) {
+	line := newGlyphLine(1, Layout{Mode: LayoutSmush, Rules: SmushEqual})
+	line.add([]string{"ab|"}, 0)
+	line.add([]string{"|cd"}, 1)
+
+	if got := string(line.rows[0]); got != "ab|cd" {
+		t.Errorf("smushed row = %q, want %q", got, "ab|cd")
+	}
+	// The shared column belongs to the later glyph
+	if got := line.owners[0][2]; got != 1 {
+		t.Errorf("owner of smushed column = %d, want 1", got)
+	}
+}
+
+func TestGenerateArtWithFontLayout(t *testing.T) {
+	charMap, err := LoadBanner("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("Failed to load banner: %v", err)
+	}
+
+	font := fontFromMap(charMap)
+	full := GenerateArtWithFont("Hello", font, "", "", "")
+	fit := GenerateArtWithFont("Hello", font.WithLayout(LayoutFit), "", "", "")
+	smush := GenerateArtWithFont("Hello", font.WithLayout(LayoutSmush), "", "", "")
+
+	if full != GenerateArt("Hello", charMap) {
+		t.Error("Full layout should match the default rendering")
+	}
+
+	fullWidth := len(strings.Split(full, "\n")[0])
+	fitWidth := len(strings.Split(fit, "\n")[0])
+	smushWidth := len(strings.Split(smush, "\n")[0])
+	if !(smushWidth < fitWidth && fitWidth < fullWidth) {
+		t.Errorf("Expected smush < fit < full widths, got %d, %d, %d", smushWidth, fitWidth, fullWidth)
+	}
+
+	// Width used for wrapping matches the rendered width (excluding $)
+	if got := font.WithLayout(LayoutFit).TextWidth("Hello"); got != fitWidth-1 {
+		t.Errorf("TextWidth() = %d, want %d", got, fitWidth-1)
+	}
+}
+
+func TestGenerateArtWithLayoutColor(t *testing.T) {
+	charMap, err := LoadBanner("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("Failed to load banner: %v", err)
+	}
+
+	result := GenerateArtWithFont("Hello", fontFromMap(charMap).WithLayout(LayoutSmush), "ll", "red", "")
+	for i, line := range strings.Split(result, "\n") {
+		if strings.Count(line, "\033[31m") != strings.Count(line, "\033[0m") {
+			t.Errorf("Line %d has unbalanced color codes: %q", i, line)
+		}
+	}
+	if !strings.Contains(result, "\033[31m") {
+		t.Error("Expected colored substring in smushed output")
+	}
+}
+
+func TestIsValidLayout(t *testing.T) {
+	for _, mode := range []string{"full", "fit", "smush"} {
+		if !IsValidLayout(mode) {
+			t.Errorf("IsValidLayout(%q) = false, want true", mode)
+		}
+	}
+	for _, mode := range []string{"", "kern", "FULL"} {
+		if IsValidLayout(mode) {
+			t.Errorf("IsValidLayout(%q) = true, want false", mode)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package ascii
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Diagnostic severities
+const (
+	SeverityError   = "error"   // the banner renders incorrectly
+	SeverityWarning = "warning" // the banner renders, but the file should be fixed
+)
+
+// Diagnostic codes reported by LintBanner
+const (
+	LintBadHeader      = "bad-header"      // the metrics header or FIGlet structure is invalid
+	LintBadSeparator   = "bad-separator"   // a separator line is neither empty nor a code tag
+	LintCRLF           = "crlf"            // the file uses CRLF line endings
+	LintDuplicateGlyph = "duplicate-glyph" // a code tag redefines a character
+	LintMissingGlyph   = "missing-glyph"   // a printable ASCII character has no glyph
+	LintNonPrintable   = "non-printable"   // a line contains a control or other non-printable character
+	LintRaggedGlyph    = "ragged-glyph"    // the rows of a glyph differ in width
+	LintTruncated      = "truncated"       // the file ends inside a glyph
+)
+
+// Diagnostic is a problem found in a banner file
+type Diagnostic struct {
+	Line     int    // 1-based line number; 0 for problems with the file as a whole
+	Severity string // SeverityError or SeverityWarning
+	Code     string // one of the Lint* codes
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s [%s]", d.Severity, d.Message, d.Code)
+	}
+	return fmt.Sprintf("%d: %s: %s [%s]", d.Line, d.Severity, d.Message, d.Code)
+}
+
+// diagnosticList collects the diagnostics of a file
+type diagnosticList []Diagnostic
+
+// add records a diagnostic with a formatted message
+func (l *diagnosticList) add(line int, severity, code, format string, args ...interface{}) {
+	*l = append(*l, Diagnostic{Line: line, Severity: severity, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// LintBanner checks a banner file and returns its diagnostics ordered by line,
+// with problems concerning the whole file last. The error is only set when the
+// file cannot be read.
+func LintBanner(filename string) ([]Diagnostic, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read banner file: %w", err)
+	}
+	return lintBannerData(data), nil
+}
+
+// HasErrors reports whether any of the diagnostics is an error
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// lintBannerData checks the contents of a banner file
+func lintBannerData(data []byte) []Diagnostic {
+	var diagnostics diagnosticList
+
+	text := strings.TrimSuffix(string(data), "\n")
+	lines := strings.Split(text, "\n")
+
+	// Line endings and characters are checked on every line
+	crlf := 0
+	for i, line := range lines {
+		if strings.HasSuffix(line, "\r") {
+			if crlf == 0 {
+				diagnostics.add(i+1, SeverityWarning, LintCRLF, "line ends with CRLF; banners should use LF line endings")
+			}
+			crlf++
+			lines[i] = strings.TrimSuffix(line, "\r")
+		}
+
+		for column, char := range []rune(lines[i]) {
+			if char == utf8.RuneError || !unicode.IsPrint(char) {
+				diagnostics.add(i+1, SeverityWarning, LintNonPrintable, "non-printable character %q in column %d", char, column+1)
+			}
+		}
+	}
+	if crlf > 1 {
+		diagnostics.add(0, SeverityWarning, LintCRLF, "%d lines end with CRLF", crlf)
+	}
+
+	if isFIGlet(lines) {
+		// The FIGlet parser reports the first structural problem
+		if _, _, err := parseFIGletLines(lines); err != nil {
+			diagnostics.add(1, SeverityError, LintBadHeader, "%v", err)
+		}
+	} else {
+		diagnostics = append(diagnostics, lintBannerLines(lines)...)
+	}
+
+	// File-level diagnostics (line 0) go last
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Line, diagnostics[j].Line
+		return a != 0 && (b == 0 || a < b)
+	})
+	return diagnostics
+}
+
+// lintBannerLines checks the structure of a plain banner, following the
+// layout parseBannerLines expects
+func lintBannerLines(lines []string) diagnosticList {
+	var diagnostics diagnosticList
+
+	font := &Font{Height: defaultHeight, Baseline: defaultBaseline}
+	first := 0 // index of the first separator line
+	if len(lines) > 0 && strings.HasPrefix(lines[0], bannerHeaderPrefix) {
+		if err := parseBannerHeader(lines[0], font); err != nil {
+			diagnostics.add(1, SeverityError, LintBadHeader, "invalid header: %v", err)
+			return diagnostics
+		}
+		first = 1
+	}
+	height := font.Height
+
+	defined := make(map[rune]int) // line of the separator declaring each glyph
+	char := rune(32)
+	for i := first; i < len(lines); i += height + 1 {
+		separator := lines[i]
+
+		if i+height >= len(lines) {
+			// Trailing blank lines are harmless; anything else is a cut-off glyph
+			for _, rest := range lines[i:] {
+				if strings.TrimSpace(rest) != "" {
+					diagnostics.add(i+1, SeverityError, LintTruncated, "file ends inside a glyph: expected %d rows after the separator, found %d", height, len(lines)-i-1)
+					break
+				}
+			}
+			break
+		}
+
+		code, tagged := parseCodeTag(separator)
+		if !tagged && separator != "" {
+			diagnostics.add(i+1, SeverityError, LintBadSeparator, "separator line should be empty or a U+XXXX code tag, found %q", separator)
+		}
+
+		// Work out which character the glyph belongs to
+		glyphChar := char
+		switch {
+		case tagged:
+			glyphChar = code
+			if line, exists := defined[code]; exists {
+				diagnostics.add(i+1, SeverityWarning, LintDuplicateGlyph, "glyph for %q (U+%04X) replaces the one declared on line %d", code, code, line)
+			}
+		case char > 126:
+			glyphChar = -1 // ignored by the parser
+			diagnostics.add(i+1, SeverityWarning, LintBadSeparator, "glyph after '~' has no code tag and is ignored")
+		default:
+			char++
+		}
+		if glyphChar >= 0 {
+			defined[glyphChar] = i + 1
+		}
+
+		// Every row of a glyph must have the width of its first row
+		width := utf8.RuneCountInString(lines[i+1])
+		for j := 2; j <= height; j++ {
+			if w := utf8.RuneCountInString(lines[i+j]); w != width {
+				diagnostics.add(i+j+1, SeverityError, LintRaggedGlyph, "row is %d columns wide, but the glyph's first row is %d", w, width)
+				break
+			}
+		}
+	}
+
+	// Collapse runs of missing ASCII characters into one diagnostic each
+	for start := rune(32); start <= 126; start++ {
+		if _, exists := defined[start]; exists {
+			continue
+		}
+		end := start
+		for end+1 <= 126 {
+			if _, exists := defined[end+1]; exists {
+				break
+			}
+			end++
+		}
+		if start == end {
+			diagnostics.add(0, SeverityError, LintMissingGlyph, "missing glyph for %q (U+%04X)", start, start)
+		} else {
+			diagnostics.add(0, SeverityError, LintMissingGlyph, "missing glyphs for %q (U+%04X) through %q (U+%04X)", start, start, end, end)
+		}
+		start = end
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,150 @@
+package ascii
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// asciiBanner builds a valid banner of the given height for characters 32-126
+func asciiBanner(height int) []string {
+	var lines []string
+	for char := 32; char <= 126; char++ {
+		lines = append(lines, "")
+		for row := 0; row < height; row++ {
+			lines = append(lines, "ab")
+		}
+	}
+	return lines
+}
+
+func TestLintBannerStockBanners(t *testing.T) {
+	for _, name := range []string{"standard", "shadow", "thinkertoy"} {
+		diagnostics, err := LintBanner(filepath.Join("../../assets", name+".txt"))
+		if err != nil {
+			t.Fatalf("LintBanner(%q) error = %v", name, err)
+		}
+		if len(diagnostics) != 0 {
+			t.Errorf("LintBanner(%q) = %v, want no diagnostics", name, diagnostics)
+		}
+	}
+}
+
+func TestLintBannerDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(lines []string) []string
+		wantLine int
+		wantCode string
+	}{
+		{"ragged glyph", func(lines []string) []string {
+			lines[3] = "abc"
+			return lines
+		}, 4, LintRaggedGlyph},
+		{"bad separator", func(lines []string) []string {
+			lines[9] = "----"
+			return lines
+		}, 10, LintBadSeparator},
+		{"non-printable", func(lines []string) []string {
+			lines[2] = "a\x01"
+			return lines
+		}, 3, LintNonPrintable},
+		{"crlf", func(lines []string) []string {
+			lines[4] += "\r"
+			return lines
+		}, 5, LintCRLF},
+		{"truncated", func(lines []string) []string {
+			return lines[:len(lines)-3]
+		}, len(asciiBanner(8)) - 8, LintTruncated},
+		{"missing glyphs", func(lines []string) []string {
+			return lines[:9*90]
+		}, 0, LintMissingGlyph},
+		{"duplicate tag", func(lines []string) []string {
+			return append(lines, append([]string{"U+0041"}, lines[1:9]...)...)
+		}, len(asciiBanner(8)) + 1, LintDuplicateGlyph},
+		{"untagged extra glyph", func(lines []string) []string {
+			return append(lines, lines[:9]...)
+		}, len(asciiBanner(8)) + 1, LintBadSeparator},
+		{"bad header", func(lines []string) []string {
+			return append([]string{"# height=zero"}, lines...)
+		}, 1, LintBadHeader},
+		{"unknown header key", func(lines []string) []string {
+			return append([]string{"# heigth=8"}, lines...)
+		}, 1, LintBadHeader},
+		{"baseline below the font", func(lines []string) []string {
+			return append([]string{"# height=8 baseline=9"}, lines...)
+		}, 1, LintBadHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := tt.edit(asciiBanner(8))
+			diagnostics := lintBannerData([]byte(strings.Join(lines, "\n") + "\n"))
+			if len(diagnostics) == 0 {
+				t.Fatal("lintBannerData() found no problems")
+			}
+			if d := diagnostics[0]; d.Code != tt.wantCode || d.Line != tt.wantLine {
+				t.Errorf("lintBannerData() first diagnostic = %v, want %s on line %d", d, tt.wantCode, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestLintBannerMissingGlyphRuns(t *testing.T) {
+	// Declare every glyph with a code tag, leaving out 'B' and 'y' through '~'
+	lines := []string{"# height=2"}
+	for char := rune(32); char < 'y'; char++ {
+		if char != 'B' {
+			lines = append(lines, fmt.Sprintf("U+%04X", char), "ab", "ab")
+		}
+	}
+
+	var messages []string
+	for _, d := range lintBannerData([]byte(strings.Join(lines, "\n"))) {
+		if d.Code == LintMissingGlyph {
+			messages = append(messages, d.Message)
+		}
+	}
+
+	want := []string{
+		"missing glyph for 'B' (U+0042)",
+		"missing glyphs for 'y' (U+0079) through '~' (U+007E)",
+	}
+	if strings.Join(messages, "|") != strings.Join(want, "|") {
+		t.Errorf("Missing glyph diagnostics = %q, want %q", messages, want)
+	}
+}
+
+func TestLintBannerHeaderHeight(t *testing.T) {
+	lines := append([]string{"# height=2 baseline=1"}, asciiBanner(2)...)
+	path := filepath.Join(t.TempDir(), "small.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+
+	diagnostics, err := LintBanner(path)
+	if err != nil {
+		t.Fatalf("LintBanner() error = %v", err)
+	}
+	if len(diagnostics) != 0 || HasErrors(diagnostics) {
+		t.Errorf("LintBanner() = %v, want no diagnostics", diagnostics)
+	}
+
+	if _, err := LintBanner(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LintBanner() of a missing file should fail")
+	}
+}
+
+func TestLintFIGlet(t *testing.T) {
+	if diagnostics := lintBannerData([]byte(buildTestFIGlet())); len(diagnostics) != 0 {
+		t.Errorf("lintBannerData() of a valid FIGlet font = %v", diagnostics)
+	}
+
+	broken := strings.Replace(buildTestFIGlet(), "flf2a$ 2", "flf2a$ x", 1)
+	diagnostics := lintBannerData([]byte(broken))
+	if len(diagnostics) != 1 || diagnostics[0].Code != LintBadHeader || !HasErrors(diagnostics) {
+		t.Errorf("lintBannerData() of a broken FIGlet font = %v", diagnostics)
+	}
+}
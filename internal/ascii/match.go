@@ -0,0 +1,100 @@
+package ascii
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Match modes selecting how Options.Substring and rule patterns are matched
+const (
+	MatchLiteral = "literal" // the pattern is plain text
+	MatchRegex   = "regex"   // the pattern is a regular expression
+)
+
+// IsValidMatchMode reports whether mode names a supported match mode
+func IsValidMatchMode(mode string) bool {
+	switch mode {
+	case MatchLiteral, MatchRegex:
+		return true
+	}
+	return false
+}
+
+// matcher finds the occurrences of a pattern in a line. Matches are spans of
+// runes, so multi-byte characters map to their own glyphs.
+type matcher struct {
+	literal    []rune         // literal pattern; nil for regular expressions
+	re         *regexp.Regexp // regular expression; nil for literals
+	ignoreCase bool
+}
+
+// compileMatcher prepares a pattern for matching in the given mode; an empty
+// mode means MatchLiteral
+func compileMatcher(pattern, mode string, ignoreCase bool) (matcher, error) {
+	if mode != MatchRegex {
+		return matcher{literal: []rune(pattern), ignoreCase: ignoreCase}, nil
+	}
+
+	expr := pattern
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return matcher{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return matcher{re: re, ignoreCase: ignoreCase}, nil
+}
+
+// spans returns the occurrences of the pattern in chars. Literal matches may
+// overlap; empty regular expression matches are dropped.
+func (m matcher) spans(chars []rune) []span {
+	if m.re != nil {
+		return regexpSpans(m.re, chars)
+	}
+	return literalSpans(m.literal, chars, m.ignoreCase)
+}
+
+// literalSpans returns every occurrence of pattern in chars, including
+// overlapping ones, optionally comparing case-insensitively
+func literalSpans(pattern, chars []rune, ignoreCase bool) []span {
+	if len(pattern) == 0 {
+		return nil
+	}
+
+	var spans []span
+	want := string(pattern)
+	for i := 0; i+len(pattern) <= len(chars); i++ {
+		candidate := string(chars[i : i+len(pattern)])
+		if candidate == want || (ignoreCase && strings.EqualFold(candidate, want)) {
+			spans = append(spans, span{i, i + len(pattern)})
+		}
+	}
+	return spans
+}
+
+// regexpSpans returns the non-empty matches of re in chars as rune spans
+func regexpSpans(re *regexp.Regexp, chars []rune) []span {
+	text := string(chars)
+
+	// runeIndex maps byte offsets of text to rune indices
+	runeIndex := make([]int, len(text)+1)
+	byteOffset := 0
+	for i, char := range chars {
+		n := len(string(char))
+		for j := 0; j < n; j++ {
+			runeIndex[byteOffset+j] = i
+		}
+		byteOffset += n
+	}
+	runeIndex[len(text)] = len(chars)
+
+	var spans []span
+	for _, match := range re.FindAllStringIndex(text, -1) {
+		if match[0] < match[1] {
+			spans = append(spans, span{runeIndex[match[0]], runeIndex[match[1]]})
+		}
+	}
+	return spans
+}
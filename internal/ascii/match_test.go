@@ -0,0 +1,99 @@
+package ascii
+
+import (
+	"reflect"
+	"testing"
+
+	"ascii-art/assets"
+)
+
+func TestMatcherSpans(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		mode       string
+		ignoreCase bool
+		text       string
+		want       []span
+	}{
+		{"literal", "ab", MatchLiteral, false, "abAB", []span{{0, 2}}},
+		{"literal ignoring case", "ab", MatchLiteral, true, "abAB", []span{{0, 2}, {2, 4}}},
+		{"overlapping literals", "aa", "", false, "aaa", []span{{0, 2}, {1, 3}}},
+		{"multi-byte literal ignoring case", "é", MatchLiteral, true, "cafÉ é", []span{{3, 4}, {5, 6}}},
+		{"digits", "[0-9]", MatchRegex, false, "a1b22", []span{{1, 2}, {3, 4}, {4, 5}}},
+		{"version", `v[0-9]+(\.[0-9]+)*`, MatchRegex, false, "ünïcode v1.2.3!", []span{{8, 14}}},
+		{"regex ignoring case", "error", MatchRegex, true, "Error ERROR", []span{{0, 5}, {6, 11}}},
+		{"empty matches dropped", "x*", MatchRegex, false, "axxb", []span{{1, 3}}},
+		{"no match", "zz", MatchLiteral, false, "abc", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := compileMatcher(tt.pattern, tt.mode, tt.ignoreCase)
+			if err != nil {
+				t.Fatalf("compileMatcher() error = %v", err)
+			}
+			if got := m.spans([]rune(tt.text)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("spans() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := compileMatcher("(", MatchRegex, false); err == nil {
+		t.Error("compileMatcher() should reject an invalid regular expression")
+	}
+}
+
+func TestRenderMatchOptions(t *testing.T) {
+	font, err := LoadFontFS(assets.Banners, "standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFontFS() error = %v", err)
+	}
+	red, _ := LookupColor("red")
+
+	tests := []struct {
+		name    string
+		text    string
+		options Options
+		want    []bool // whether the cells of each source rune are colored
+	}{
+		{"regex substring", "a1b2", Options{Substring: "[0-9]", Match: MatchRegex}, []bool{false, true, false, true}},
+		{"case-insensitive substring", "aBab", Options{Substring: "ab", IgnoreCase: true}, []bool{true, true, true, true}},
+		{"case-insensitive rules", "ERR err", Options{Rules: []ColorRule{{Pattern: "err", Color: "red"}}, IgnoreCase: true}, []bool{true, true, true, false, true, true, true}},
+		{"regex rules", "a1b", Options{Rules: []ColorRule{{Pattern: "[0-9]", Color: "red"}}, Match: MatchRegex}, []bool{false, true, false}},
+		// The substring follows multi-byte characters the standard banner
+		// cannot draw; matches are still counted in runes
+		{"after multi-byte characters", "éé ab", Options{Substring: "b"}, []bool{false, false, false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.Width = 200
+			if tt.options.Rules == nil {
+				tt.options.Color = "red"
+			}
+			renderer, err := NewRenderer(font, tt.options)
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+			result, err := renderer.Render(tt.text)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			for _, row := range result.Canvas.Rows {
+				for _, cell := range row {
+					if colored := cell.Style.Foreground == red; colored != tt.want[cell.Source] {
+						t.Fatalf("cell %q of rune %d colored = %v, want %v", cell.Rune, cell.Source, colored, tt.want[cell.Source])
+					}
+				}
+			}
+		})
+	}
+
+	for _, options := range []Options{{Match: "glob"}, {Substring: "(", Match: MatchRegex}} {
+		if _, err := NewRenderer(font, options); err == nil {
+			t.Errorf("NewRenderer(%+v) should fail", options)
+		}
+	}
+}
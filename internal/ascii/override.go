@@ -0,0 +1,94 @@
+package ascii
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// overridesExtension is the extension of the file holding a banner's glyph overrides
+const overridesExtension = ".overrides"
+
+// overridesFile returns the overrides file that belongs to a banner file,
+// e.g. "assets/standard.overrides" for "assets/standard.txt"
+func overridesFile(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + overridesExtension
+}
+
+// WithGlyphs returns a copy of the font in which the given glyphs replace or
+// add to the font's own
+func (f *Font) WithGlyphs(glyphs map[rune][]string) *Font {
+	font := *f
+	font.Glyphs = make(map[rune][]string, len(f.Glyphs)+len(glyphs))
+	for char, glyph := range f.Glyphs {
+		font.Glyphs[char] = glyph
+	}
+	for char, glyph := range glyphs {
+		font.Glyphs[char] = glyph
+	}
+	return &font
+}
+
+// applyOverrides applies the overrides file returned by open to the font. A
+// missing overrides file leaves the font unchanged.
+func applyOverrides(font *Font, open func() (io.ReadCloser, error)) (*Font, error) {
+	file, err := open()
+	if errors.Is(err, fs.ErrNotExist) {
+		return font, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open overrides file: %w", err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read overrides file: %w", err)
+	}
+
+	glyphs, err := parseOverrides(lines, font.Height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse overrides file: %w", err)
+	}
+	return font.WithGlyphs(glyphs), nil
+}
+
+// parseOverrides reads glyph overrides. Leading lines starting with "#" are
+// comments; every glyph is then a "U+XXXX" code tag followed by height rows,
+// and blank lines between glyphs are ignored.
+func parseOverrides(lines []string, height int) (map[rune][]string, error) {
+	glyphs := make(map[rune][]string)
+
+	i := 0
+	for i < len(lines) && strings.HasPrefix(lines[i], "#") {
+		i++
+	}
+
+	for i < len(lines) {
+		if strings.TrimSpace(lines[i]) == "" {
+			i++
+			continue
+		}
+
+		code, tagged := parseCodeTag(lines[i])
+		if !tagged {
+			return nil, fmt.Errorf("line %d: expected a U+XXXX code tag, found %q", i+1, lines[i])
+		}
+		if i+height >= len(lines) {
+			return nil, fmt.Errorf("line %d: glyph for U+%04X has %d of %d rows", i+1, code, len(lines)-i-1, height)
+		}
+
+		glyphs[code] = append([]string(nil), lines[i+1:i+1+height]...)
+		i += height + 1
+	}
+
+	return glyphs, nil
+}
@@ -0,0 +1,108 @@
+package ascii
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStandardUnderscoreOverride(t *testing.T) {
+	want := []string{"         ", "         ", "         ", "         ", "         ", "         ", " _______ ", "|_______|"}
+
+	standard, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+	if got := standard.Glyphs['_']; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("standard underscore = %q, want %q", got, want)
+	}
+
+	// The embedded copy carries the same override
+	embedded, err := NewResolver().ResolveName("standard")
+	if err != nil {
+		t.Fatalf("ResolveName() error = %v", err)
+	}
+	if got := embedded.Glyphs['_']; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("embedded standard underscore = %q, want %q", got, want)
+	}
+
+	// Other banners keep their own underscore
+	shadow, err := LoadFont("../../assets/shadow.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+	if got := shadow.Glyphs['_'][7]; strings.Contains(got, "|_______|") {
+		t.Errorf("shadow underscore was patched: %q", shadow.Glyphs['_'])
+	}
+}
+
+func TestOverridesFile(t *testing.T) {
+	dir := t.TempDir()
+	banner := "# height=2\n" + strings.Repeat("\nab\nab", 95) + "\n"
+	overrides := "# comment\nU+0041\nAA\nAA\n\nu+00e9\nee\nee\n"
+	if err := os.WriteFile(filepath.Join(dir, "mini.txt"), []byte(banner), 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mini.overrides"), []byte(overrides), 0644); err != nil {
+		t.Fatalf("Failed to write overrides: %v", err)
+	}
+
+	font, err := LoadFont(filepath.Join(dir, "mini.txt"))
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+	if got := font.Glyphs['A']; got[0] != "AA" {
+		t.Errorf("Replaced glyph A = %q", got)
+	}
+	if got := font.Glyphs['é']; len(got) != 2 || got[0] != "ee" {
+		t.Errorf("Added glyph é = %q", got)
+	}
+	if got := font.Glyphs['B']; got[0] != "ab" {
+		t.Errorf("Untouched glyph B = %q", got)
+	}
+
+	fsys := fstest.MapFS{
+		"mini.txt":       {Data: []byte(banner)},
+		"mini.overrides": {Data: []byte(overrides)},
+	}
+	font, err = LoadFontFS(fsys, "mini.txt")
+	if err != nil {
+		t.Fatalf("LoadFontFS() error = %v", err)
+	}
+	if got := font.Glyphs['A']; got[0] != "AA" {
+		t.Errorf("LoadFontFS() glyph A = %q", got)
+	}
+}
+
+func TestParseOverridesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{"missing code tag", []string{"x", "aa", "aa"}},
+		{"short glyph", []string{"U+0041", "aa"}},
+		{"comment after glyph", []string{"U+0041", "aa", "aa", "# note"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseOverrides(tt.lines, 2); err == nil {
+				t.Error("parseOverrides() expected error")
+			}
+		})
+	}
+}
+
+func TestWithGlyphsCopiesFont(t *testing.T) {
+	font := NewFont("mini", map[rune][]string{'a': {"a"}})
+	changed := font.WithGlyphs(map[rune][]string{'a': {"A"}, 'b': {"b"}})
+
+	if font.Glyphs['a'][0] != "a" || font.Has('b') {
+		t.Error("WithGlyphs() modified the original font")
+	}
+	if changed.Glyphs['a'][0] != "A" || !changed.Has('b') {
+		t.Errorf("WithGlyphs() = %v", changed.Glyphs)
+	}
+}
@@ -0,0 +1,90 @@
+package ascii
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// themes are the named palettes, cycled one color per character
+var themes = map[string][]Color{
+	"rainbow": {
+		RGBColor(0xff, 0x00, 0x00), RGBColor(0xff, 0x7f, 0x00), RGBColor(0xff, 0xff, 0x00),
+		RGBColor(0x00, 0xff, 0x00), RGBColor(0x00, 0x00, 0xff), RGBColor(0x4b, 0x00, 0x82),
+		RGBColor(0x8f, 0x00, 0xff),
+	},
+	"fire": {
+		RGBColor(0xff, 0xd7, 0x00), RGBColor(0xff, 0x8c, 0x00), RGBColor(0xff, 0x45, 0x00),
+		RGBColor(0xdc, 0x14, 0x3c), RGBColor(0x8b, 0x00, 0x00),
+	},
+	"ocean": {
+		RGBColor(0x00, 0xce, 0xd1), RGBColor(0x00, 0xbf, 0xff), RGBColor(0x1e, 0x90, 0xff),
+		RGBColor(0x41, 0x69, 0xe1), RGBColor(0x00, 0x00, 0x80),
+	},
+	"forest": {
+		RGBColor(0x9a, 0xcd, 0x32), RGBColor(0x6b, 0x8e, 0x23), RGBColor(0x22, 0x8b, 0x22),
+		RGBColor(0x2e, 0x8b, 0x57), RGBColor(0x00, 0x64, 0x00),
+	},
+	"pastel": {
+		RGBColor(0xff, 0xb3, 0xba), RGBColor(0xff, 0xdf, 0xba), RGBColor(0xff, 0xff, 0xba),
+		RGBColor(0xba, 0xff, 0xc9), RGBColor(0xba, 0xe1, 0xff),
+	},
+	"ansi": {
+		{Type: ColorBasic, Index: 1}, {Type: ColorBasic, Index: 3}, {Type: ColorBasic, Index: 2},
+		{Type: ColorBasic, Index: 6}, {Type: ColorBasic, Index: 4}, {Type: ColorBasic, Index: 5},
+	},
+}
+
+// ThemeNames returns the names of the built-in palettes, sorted
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParsePalette parses a palette: the name of a theme such as "rainbow", or a
+// comma-separated list of colors in any notation ParseColor accepts
+func ParsePalette(spec string) ([]Color, error) {
+	if colors, exists := themes[strings.ToLower(strings.TrimSpace(spec))]; exists {
+		return colors, nil
+	}
+
+	var colors []Color
+	for _, item := range splitColorList(spec) {
+		color, err := ParseColor(item)
+		if err != nil {
+			if !strings.Contains(spec, ",") {
+				return nil, fmt.Errorf("%w %q: not a theme (%s) or a color", ErrInvalidColor, spec, strings.Join(ThemeNames(), ", "))
+			}
+			return nil, err
+		}
+		colors = append(colors, color)
+	}
+	return colors, nil
+}
+
+// applyPalette colors the cells of the canvas produced by selected runes of
+// text, cycling through the palette one source character at a time.
+// Whitespace characters take no color and do not advance the cycle.
+func applyPalette(canvas *Canvas, palette []Color, text []rune, selected []bool) {
+	colors := make(map[int]Color) // color of every selected source rune
+	next := 0
+	for i, isSelected := range selected {
+		if isSelected && i < len(text) && !unicode.IsSpace(text[i]) {
+			colors[i] = palette[next%len(palette)]
+			next++
+		}
+	}
+
+	for _, row := range canvas.Rows {
+		for x, cell := range row {
+			if color, exists := colors[cell.Source]; exists {
+				row[x].Style.Foreground = color
+			}
+		}
+	}
+}
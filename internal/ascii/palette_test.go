@@ -0,0 +1,126 @@
+package ascii
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"ascii-art/assets"
+)
+
+func TestParsePalette(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    int // number of colors
+		wantErr bool
+	}{
+		{"rainbow", 7, false},
+		{"Ocean", 5, false},
+		{"red,#00ff00,rgb(0,0,255)", 3, false},
+		{"red", 1, false},
+		{"nope", 0, true},
+		{"red,nope", 0, true},
+		{"red,", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			palette, err := ParsePalette(tt.spec)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidColor) {
+					t.Errorf("ParsePalette(%q) error = %v, want %v", tt.spec, err, ErrInvalidColor)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePalette(%q) error = %v", tt.spec, err)
+			}
+			if len(palette) != tt.want {
+				t.Errorf("ParsePalette(%q) has %d colors, want %d", tt.spec, len(palette), tt.want)
+			}
+		})
+	}
+
+	for _, name := range ThemeNames() {
+		if _, err := ParsePalette(name); err != nil {
+			t.Errorf("ParsePalette(%q) error = %v", name, err)
+		}
+	}
+}
+
+func TestRenderPalette(t *testing.T) {
+	font, err := LoadFontFS(assets.Banners, "standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFontFS() error = %v", err)
+	}
+	red, green, blue := RGBColor(255, 0, 0), RGBColor(0, 255, 0), RGBColor(0, 0, 255)
+
+	tests := []struct {
+		name      string
+		text      string
+		substring string
+		want      map[int]Color // color of the cells of each source rune; missing runes are uncolored
+	}{
+		{"cycles per character", "abcd", "", map[int]Color{0: red, 1: green, 2: blue, 3: red}},
+		{"spaces keep the cycle", "a b", "", map[int]Color{0: red, 2: green}},
+		{"continues across lines", "ab\\nc", "", map[int]Color{0: red, 1: green, 4: blue}},
+		{"substring only", "xabxb", "b", map[int]Color{2: red, 4: green}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := NewRenderer(font, Options{Palette: "#ff0000,#00ff00,#0000ff", Substring: tt.substring, Width: 200})
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+			result, err := renderer.Render(tt.text)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			for _, row := range result.Canvas.Rows {
+				for _, cell := range row {
+					if got, want := cell.Style.Foreground, tt.want[cell.Source]; got != want {
+						t.Fatalf("cell %q of rune %d = %+v, want %+v", cell.Rune, cell.Source, got, want)
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := NewRenderer(font, Options{Palette: "rainbow", Color: "red"}); err == nil {
+		t.Error("NewRenderer() should reject a palette combined with a color")
+	}
+}
+
+func TestPaletteEscapeSequences(t *testing.T) {
+	font, err := LoadFontFS(assets.Banners, "standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFontFS() error = %v", err)
+	}
+	renderer, err := NewRenderer(font, Options{Palette: "rainbow", Layout: LayoutSmush, Width: 200})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	result, err := renderer.Render("Hello")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// Adjacent characters of different colors never share an open sequence:
+	// every color is reset before the next one starts
+	for _, row := range result.Rows() {
+		open := false
+		for _, part := range strings.Split(row, "\033[")[1:] {
+			isReset := strings.HasPrefix(part, "0m")
+			if open == isReset {
+				open = !isReset
+				continue
+			}
+			t.Fatalf("unbalanced escape sequences in row %q", row)
+		}
+		if open {
+			t.Fatalf("row %q ends with an open color", row)
+		}
+	}
+}
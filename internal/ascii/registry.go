@@ -0,0 +1,115 @@
+package ascii
+
+import (
+	"os"
+	"sync"
+)
+
+// Registry caches the fonts found by a resolver so that every banner is
+// parsed once. It is safe for concurrent use. Fonts returned by a registry
+// are shared between callers and must not be modified; use the With*
+// methods to derive variants.
+type Registry struct {
+	resolver *Resolver
+	reload   bool // look the banner up again and reload it when its files change
+
+	mu    sync.RWMutex
+	fonts map[registryKey]*cachedFont
+}
+
+// registryKey identifies a lookup: the same string may be a name in one
+// lookup and a path in another
+type registryKey struct {
+	banner   string
+	nameOnly bool
+}
+
+// cachedFont is a parsed font together with the version of the files it was read from
+type cachedFont struct {
+	font    *Font
+	version fontVersion
+}
+
+// fontVersion identifies the files a font was loaded from. Embedded banners
+// never change and have zero modification times.
+type fontVersion struct {
+	source    fontSource
+	modTime   int64 // of the banner file, in nanoseconds since the epoch
+	overrides int64 // of the overrides file; 0 when there is none
+}
+
+// NewRegistry creates a registry for the banners of resolver. With reload
+// set, every lookup checks whether the banner now resolves to another file,
+// or whether the banner or its overrides file was modified, and parses the
+// banner again if so.
+func NewRegistry(resolver *Resolver, reload bool) *Registry {
+	return &Registry{
+		resolver: resolver,
+		reload:   reload,
+		fonts:    make(map[registryKey]*cachedFont),
+	}
+}
+
+// Font returns the banner given by name or by file path, like Resolver.Resolve
+func (r *Registry) Font(banner string) (*Font, error) {
+	return r.get(registryKey{banner: banner})
+}
+
+// FontByName returns the banner with the given name, like Resolver.ResolveName
+func (r *Registry) FontByName(name string) (*Font, error) {
+	return r.get(registryKey{banner: name, nameOnly: true})
+}
+
+// List returns the banners available by name, like Resolver.List
+func (r *Registry) List() ([]FontInfo, error) {
+	return r.resolver.List()
+}
+
+// get returns the cached font for key, loading it on first use and, in
+// reload mode, whenever its files have changed
+func (r *Registry) get(key registryKey) (*Font, error) {
+	r.mu.RLock()
+	cached, exists := r.fonts[key]
+	r.mu.RUnlock()
+	if exists && !r.reload {
+		return cached.font, nil
+	}
+
+	source, err := r.resolver.locate(key.banner, key.nameOnly)
+	if err != nil {
+		return nil, err
+	}
+	version := sourceVersion(source)
+	if exists && cached.version == version {
+		return cached.font, nil
+	}
+
+	// Load under the write lock so that concurrent callers parse the banner once
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, exists := r.fonts[key]; exists && cached.version == version {
+		return cached.font, nil
+	}
+
+	font, err := r.resolver.load(source)
+	if err != nil {
+		return nil, err
+	}
+	r.fonts[key] = &cachedFont{font: font, version: version}
+	return font, nil
+}
+
+// sourceVersion reads the modification times of a banner file and its overrides file
+func sourceVersion(source fontSource) fontVersion {
+	version := fontVersion{source: source}
+	if source.embedded {
+		return version
+	}
+	if info, err := os.Stat(source.path); err == nil {
+		version.modTime = info.ModTime().UnixNano()
+	}
+	if info, err := os.Stat(overridesFile(source.path)); err == nil {
+		version.overrides = info.ModTime().UnixNano()
+	}
+	return version
+}
@@ -0,0 +1,111 @@
+package ascii
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistryCachesFonts(t *testing.T) {
+	registry := NewRegistry(NewResolver(), false)
+
+	first, err := registry.FontByName("standard")
+	if err != nil {
+		t.Fatalf("FontByName() error = %v", err)
+	}
+	second, err := registry.FontByName("standard")
+	if err != nil {
+		t.Fatalf("FontByName() error = %v", err)
+	}
+	if first != second {
+		t.Error("FontByName() parsed the banner again instead of using the cache")
+	}
+
+	if _, err := registry.FontByName("nonexistent"); !errors.Is(err, ErrBannerNotFound) {
+		t.Errorf("FontByName() error = %v, want %v", err, ErrBannerNotFound)
+	}
+}
+
+func TestRegistryReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "custom.txt")
+	if err := os.WriteFile(path, []byte("# height=1\n\nx\n"), 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+
+	cached := NewRegistry(NewResolver(dir), false)
+	watched := NewRegistry(NewResolver(dir), true)
+	for _, registry := range []*Registry{cached, watched} {
+		if _, err := registry.FontByName("custom"); err != nil {
+			t.Fatalf("FontByName() error = %v", err)
+		}
+	}
+
+	// Rewrite the banner with a later modification time
+	if err := os.WriteFile(path, []byte("# height=2\n\nx\nx\n"), 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("Failed to change modification time: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		registry   *Registry
+		wantHeight int
+	}{
+		{"without reload", cached, 1},
+		{"with reload", watched, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font, err := tt.registry.FontByName("custom")
+			if err != nil {
+				t.Fatalf("FontByName() error = %v", err)
+			}
+			if font.Height != tt.wantHeight {
+				t.Errorf("FontByName() height = %d, want %d", font.Height, tt.wantHeight)
+			}
+		})
+	}
+
+	// A reloading registry notices when the banner disappears
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove banner: %v", err)
+	}
+	if _, err := watched.FontByName("custom"); !errors.Is(err, ErrBannerNotFound) {
+		t.Errorf("FontByName() error = %v, want %v", err, ErrBannerNotFound)
+	}
+}
+
+func TestRegistryConcurrentAccess(t *testing.T) {
+	registry := NewRegistry(NewResolver(), true)
+
+	var wg sync.WaitGroup
+	fonts := make([]*Font, 32)
+	for i := range fonts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			banner := []string{"standard", "shadow", "thinkertoy"}[i%3]
+			font, err := registry.FontByName(banner)
+			if err != nil {
+				t.Errorf("FontByName(%q) error = %v", banner, err)
+				return
+			}
+			fonts[i] = font
+		}(i)
+	}
+	wg.Wait()
+
+	// Every lookup of a banner returns the single cached font
+	for i := 3; i < len(fonts); i++ {
+		if fonts[i] != fonts[i%3] {
+			t.Errorf("lookup %d returned a different font than lookup %d", i, i%3)
+		}
+	}
+}
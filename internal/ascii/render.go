@@ -0,0 +1,398 @@
+package ascii
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Options configures a Renderer. The zero value renders plain, left-aligned
+// text without wrapping, using the font's own layout.
+type Options struct {
+	Color     string // color name, #hex, rgb(), hsl() or ansi256:N; empty for no color
+	Substring string // when set, only occurrences of Substring are colored
+	Alignment string // left, right, center or justify; empty for left
+	Width     int    // output width in columns; 0 for no wrapping, aligning within the widest line
+	Layout    string // full, fit or smush; empty keeps the font's layout
+	Fallback  string // policy for missing glyphs; empty keeps the font's policy
+	Output    string // dollar, none or trim; empty for none
+
+	// Gradient colors the text with a comma-separated list of color stops
+	// blended across the output, like "#ff0000,#0000ff". It replaces Color
+	// and, with a substring, only colors the substring's occurrences.
+	Gradient      string
+	GradientAngle float64 // direction in degrees: 0 runs left to right, 90 top to bottom
+	GradientSpace string  // rgb or perceptual; empty for rgb
+
+	// Palette cycles through colors one character at a time: "rainbow" or
+	// another theme name, or a comma-separated list of colors. Like
+	// Gradient, it replaces Color and respects Substring.
+	Palette string
+
+	// Rules color the occurrences of further patterns. They take precedence
+	// over Color, Gradient and Palette, and where the matches of several
+	// rules overlap the later rule wins.
+	Rules []ColorRule
+
+	Background string // background color in any notation Color accepts; empty for none
+	Attributes string // comma-separated text attributes: bold, dim, italic, underline, blink, inverse
+
+	Match      string // literal or regex: how Substring and rule patterns match; empty for literal
+	IgnoreCase bool   // match Substring and rule patterns regardless of case
+
+	Wrap string // greedy, balanced or none: how lines wider than Width break; empty for balanced, none without a Width
+
+	// Canvas renders into a fixed number of columns and rows, replacing
+	// Width; text is wrapped and aligned within it and cropped if too long.
+	// In dollar mode the $ terminators take the last column; the trim mode
+	// cannot be used.
+	Canvas  CanvasSize
+	VAlign  string  // top, middle or bottom: where text sits on the canvas; empty for top
+	Padding Spacing // space around the text, which takes the background color
+	Margin  Spacing // blank space around the padding
+
+	// Hyphenate ends the rows of a word too wide to fit on one row with a
+	// hyphen glyph. Lines otherwise break only at spaces and hyphens.
+	Hyphenate bool
+}
+
+// Renderer converts text to ASCII art with a font and a fixed set of options
+type Renderer struct {
+	font      *Font
+	options   Options
+	style     Style       // parsed Options.Color, Background and Attributes
+	gradient  *Gradient   // parsed gradient options; nil for none
+	palette   []Color     // parsed Options.Palette; nil for none
+	rules     []colorRule // compiled Options.Rules
+	substring matcher     // compiled Options.Substring
+}
+
+// Result is the rendered form of a text
+type Result struct {
+	Height int     // number of rows in each rendered line
+	Canvas *Canvas // every output row; an empty input line is a single empty row
+	output string  // output mode applied when encoding
+}
+
+// NewRenderer creates a renderer for the font, validating the options
+func NewRenderer(font *Font, options Options) (*Renderer, error) {
+	if font == nil {
+		return nil, fmt.Errorf("renderer requires a font")
+	}
+	if options.Alignment != "" && !isValidAlignment(options.Alignment) {
+		return nil, fmt.Errorf("invalid alignment %q", options.Alignment)
+	}
+	if options.Layout != "" && !IsValidLayout(options.Layout) {
+		return nil, fmt.Errorf("invalid layout %q", options.Layout)
+	}
+	if options.Fallback != "" && !IsValidFallback(options.Fallback) {
+		return nil, fmt.Errorf("invalid fallback policy %q", options.Fallback)
+	}
+	if options.Output != "" && !IsValidOutputMode(options.Output) {
+		return nil, fmt.Errorf("invalid output mode %q", options.Output)
+	}
+	if options.Wrap != "" && !IsValidWrap(options.Wrap) {
+		return nil, fmt.Errorf("invalid wrap mode %q", options.Wrap)
+	}
+	if options.Width < 0 {
+		return nil, fmt.Errorf("invalid width %d", options.Width)
+	}
+	if options.VAlign != "" && !IsValidVAlign(options.VAlign) {
+		return nil, fmt.Errorf("invalid vertical alignment %q", options.VAlign)
+	}
+	for _, spacing := range []Spacing{options.Padding, options.Margin} {
+		if spacing.Top < 0 || spacing.Right < 0 || spacing.Bottom < 0 || spacing.Left < 0 {
+			return nil, fmt.Errorf("invalid spacing %+v", spacing)
+		}
+	}
+	if !options.Canvas.IsZero() {
+		if options.Width != 0 {
+			return nil, fmt.Errorf("only one of a width and a canvas can be used")
+		}
+		if options.Output == OutputTrim {
+			return nil, fmt.Errorf("a canvas keeps its trailing spaces and cannot use the trim output mode")
+		}
+		if options.Canvas.Width <= 0 || options.Canvas.Height <= 0 {
+			return nil, fmt.Errorf("invalid canvas size %dx%d", options.Canvas.Width, options.Canvas.Height)
+		}
+		columns := options.Canvas.Width - options.Margin.horizontal() - options.Padding.horizontal()
+		if options.Output == OutputDollar {
+			columns--
+		}
+		if columns < 1 || options.Canvas.Height-options.Margin.vertical()-options.Padding.vertical() < 1 {
+			return nil, fmt.Errorf("canvas %dx%d leaves no room for text inside its margins and padding", options.Canvas.Width, options.Canvas.Height)
+		}
+	}
+	coloring := 0
+	for _, option := range []string{options.Color, options.Gradient, options.Palette} {
+		if option != "" {
+			coloring++
+		}
+	}
+	if coloring > 1 {
+		return nil, fmt.Errorf("only one of a color, a gradient and a palette can be used")
+	}
+
+	var style Style
+	if options.Color != "" {
+		var err error
+		if style.Foreground, err = ParseColor(options.Color); err != nil {
+			return nil, err
+		}
+	}
+	if options.Background != "" {
+		var err error
+		if style.Background, err = ParseColor(options.Background); err != nil {
+			return nil, err
+		}
+	}
+	if options.Attributes != "" {
+		var err error
+		if style.Attrs, err = ParseAttrs(options.Attributes); err != nil {
+			return nil, err
+		}
+	}
+	var gradient *Gradient
+	if options.Gradient != "" {
+		if options.GradientSpace != "" && !IsValidInterpolation(options.GradientSpace) {
+			return nil, fmt.Errorf("invalid gradient space %q", options.GradientSpace)
+		}
+		stops, err := ParseGradient(options.Gradient)
+		if err != nil {
+			return nil, err
+		}
+		gradient = &Gradient{Stops: stops, Angle: options.GradientAngle, Space: options.GradientSpace}
+	}
+
+	if options.Layout != "" {
+		font = font.WithLayout(options.Layout)
+	}
+	if options.Fallback != "" {
+		font = font.WithFallback(options.Fallback)
+	}
+
+	var palette []Color
+	if options.Palette != "" {
+		var err error
+		if palette, err = ParsePalette(options.Palette); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.Match != "" && !IsValidMatchMode(options.Match) {
+		return nil, fmt.Errorf("invalid match mode %q", options.Match)
+	}
+	substring, err := compileMatcher(options.Substring, options.Match, options.IgnoreCase)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]colorRule, len(options.Rules))
+	for i, rule := range options.Rules {
+		if rules[i], err = compileColorRule(rule, options.Match, options.IgnoreCase); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Renderer{font: font, options: options, style: style, gradient: gradient, palette: palette, rules: rules, substring: substring}, nil
+}
+
+// Font returns the font used by the renderer, with layout and fallback options applied
+func (r *Renderer) Font() *Font {
+	return r.font
+}
+
+// Render converts text to ASCII art. Literal "\n" sequences start a new line.
+// With the error fallback policy, a *MissingGlyphError is returned for the
+// first character the font cannot render.
+func (r *Renderer) Render(text string) (*Result, error) {
+	result := &Result{Height: r.font.Height, Canvas: &Canvas{}, output: r.options.Output}
+	if text == "" {
+		if r.isFramed() {
+			// A canvas keeps its size even without text
+			result.Canvas.Rows = r.frame(nil)
+		}
+		return result, nil
+	}
+
+	if r.font.Fallback == FallbackError {
+		if err := CheckCoverage(text, r.font); err != nil {
+			return nil, err
+		}
+	}
+
+	termWidth := r.options.Width
+	if !r.options.Canvas.IsZero() {
+		termWidth = r.textWidth()
+	} else if termWidth == 0 {
+		// Lines are aligned within the widest one, leaving room for the $ terminator
+		for _, line := range strings.Split(text, "\\n") {
+			if width := r.font.TextWidth(line) + 1; width > termWidth {
+				termWidth = width
+			}
+		}
+	} else if r.options.Output != OutputDollar {
+		// The aligners leave the last column to the $ terminator; without
+		// one, rows may use every column
+		termWidth++
+	}
+
+	// Gradients and palettes color the selected runes once the canvas is laid out
+	painted := r.gradient != nil || r.palette != nil
+
+	offset := 0         // index of the line's first rune within text
+	var selected []bool // runes of text a gradient or palette colors, by index
+	for _, line := range strings.Split(text, "\\n") {
+		chars := []rune(line)
+		if len(chars) == 0 {
+			result.Canvas.Rows = append(result.Canvas.Rows, nil)
+		} else {
+			result.Canvas.Rows = append(result.Canvas.Rows, r.renderLine(chars, offset, termWidth)...)
+		}
+		if painted {
+			selected = append(selected, r.paintSelection(chars)...)
+			selected = append(selected, false, false) // the "\n" separator
+		}
+		offset += len(chars) + 2 // the "\n" separator
+	}
+
+	// The gradient depends on where cells end up, so it is applied last
+	if r.gradient != nil {
+		r.gradient.apply(result.Canvas, selected)
+	}
+	if r.palette != nil {
+		applyPalette(result.Canvas, r.palette, []rune(text), selected)
+	}
+	if r.isFramed() {
+		result.Canvas.Rows = r.frame(result.Canvas.Rows)
+	}
+	return result, nil
+}
+
+// renderLine renders a single input line, wrapped and aligned to the terminal width
+func (r *Renderer) renderLine(chars []rune, offset, termWidth int) [][]Cell {
+	styles := r.lineStyles(chars)
+
+	// Justified lines are laid out word by word
+	if r.options.Alignment == "justify" {
+		return r.justify(chars, offset, styles, termWidth)
+	}
+
+	var rows [][]Cell
+	for _, segment := range r.segments(chars, termWidth) {
+		block := r.cells(chars, segment, offset, styles)
+		rows = append(rows, alignRows(block, r.options.Alignment, termWidth)...)
+	}
+	return rows
+}
+
+// cells joins the glyphs of chars[s.start:s.end] into font.Height rows of
+// cells, styling every cell like the character that produced it. A hyphen
+// glyph ending a split word is styled like the word's last character.
+func (r *Renderer) cells(chars []rune, s segment, offset int, styles []Style) [][]Cell {
+	line := newGlyphLine(r.font.Height, r.font.Layout)
+	for i := s.start; i < s.end; i++ {
+		if charLines, exists := r.font.glyph(chars[i]); exists {
+			line.add(charLines, i)
+		}
+	}
+	if s.hyphen {
+		if charLines, exists := r.font.glyph('-'); exists {
+			line.add(charLines, s.end-1)
+		}
+	}
+
+	rows := make([][]Cell, r.font.Height)
+	for i := range rows {
+		row := make([]Cell, len(line.rows[i]))
+		for col, ch := range line.rows[i] {
+			if ch == r.font.Layout.Hardblank {
+				ch = ' '
+			}
+			owner := line.owners[i][col]
+			row[col] = Cell{Rune: ch, Style: styles[owner], Source: offset + owner}
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// lineStyles returns the style of every character of a line. With a
+// substring, only its occurrences take the color, background and
+// attributes; rules then recolor the foreground of their matches in order.
+func (r *Renderer) lineStyles(chars []rune) []Style {
+	styles := make([]Style, len(chars))
+	if !r.style.IsZero() {
+		for i, selected := range r.lineSelection(chars) {
+			if selected {
+				styles[i] = r.style
+			}
+		}
+	}
+
+	for _, rule := range r.rules {
+		for _, match := range rule.matches(chars) {
+			for i := match.start; i < match.end; i++ {
+				styles[i].Foreground = rule.style.Foreground
+			}
+		}
+	}
+	return styles
+}
+
+// lineSelection reports which characters of a line are styled by the
+// options: every character, or with a substring only those of its matches
+func (r *Renderer) lineSelection(chars []rune) []bool {
+	selected := make([]bool, len(chars))
+	if r.options.Substring == "" {
+		for i := range selected {
+			selected[i] = true
+		}
+	}
+	for _, match := range r.substring.spans(chars) {
+		for i := match.start; i < match.end; i++ {
+			selected[i] = true
+		}
+	}
+	return selected
+}
+
+// paintSelection reports which characters of a line the gradient or palette
+// colors: those of lineSelection, except the ones a rule recolors
+func (r *Renderer) paintSelection(chars []rune) []bool {
+	selected := r.lineSelection(chars)
+	for _, rule := range r.rules {
+		for _, match := range rule.matches(chars) {
+			for i := match.start; i < match.end; i++ {
+				selected[i] = false
+			}
+		}
+	}
+	return selected
+}
+
+// Rows returns all rendered rows in order, colored with ANSI escape sequences
+// and ended as the output mode requires
+func (res *Result) Rows() []string {
+	if len(res.Canvas.Rows) == 0 {
+		return nil
+	}
+	return strings.Split(res.String(), "\n")
+}
+
+// String returns the rendered rows colored with ANSI escape sequences and joined by newlines
+func (res *Result) String() string {
+	return res.Encode(ANSIEncoder{})
+}
+
+// Encode serializes the rendered rows with enc in the renderer's output mode, joined by newlines
+func (res *Result) Encode(enc Encoder) string {
+	return res.Canvas.Encode(enc, res.output)
+}
+
+// isValidAlignment checks if the alignment type is valid
+func isValidAlignment(alignment string) bool {
+	switch alignment {
+	case "left", "right", "center", "justify":
+		return true
+	}
+	return false
+}
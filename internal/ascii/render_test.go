@@ -0,0 +1,312 @@
+package ascii
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewRendererValidation(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		options Options
+		wantErr bool
+	}{
+		{"zero options", Options{}, false},
+		{"all options", Options{Color: "red", Substring: "l", Alignment: "center", Width: 120, Layout: "fit", Fallback: "placeholder", Output: "trim"}, false},
+		{"invalid alignment", Options{Alignment: "middle"}, true},
+		{"invalid layout", Options{Layout: "kern"}, true},
+		{"invalid fallback", Options{Fallback: "ignore"}, true},
+		{"negative width", Options{Width: -1}, true},
+		{"invalid output mode", Options{Output: "crlf"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRenderer(font, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRenderer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewRenderer(nil, Options{}); err == nil {
+		t.Error("NewRenderer(nil) expected error")
+	}
+}
+
+func TestRendererRender(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	renderer, err := NewRenderer(font, Options{Width: 200})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	result, err := renderer.Render("Hi\\n\\nBye")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if result.Height != 8 {
+		t.Errorf("Height = %d, want 8", result.Height)
+	}
+	if result.Canvas.Height() != 17 {
+		t.Fatalf("Render() returned %d rows, want 17", result.Canvas.Height())
+	}
+	if len(result.Canvas.Rows[8]) != 0 {
+		t.Errorf("Empty input line rendered as %q", result.Canvas.Text(8))
+	}
+
+	// The structured result carries no $ markers, but matches the legacy output otherwise
+	legacy := strings.Split(renderArt(t, "Hi\\n\\nBye", font, "", "", ""), "\n")
+	rows := result.Rows()
+	if len(rows) != len(legacy) {
+		t.Fatalf("Rows() returned %d rows, want %d", len(rows), len(legacy))
+	}
+	for i := range rows {
+		if rows[i]+"$" != legacy[i] && !(rows[i] == "" && legacy[i] == "$") {
+			t.Errorf("Row %d = %q, legacy %q", i, rows[i], legacy[i])
+		}
+	}
+	if strings.Contains(result.String(), "$") {
+		t.Errorf("String() should not contain $ markers: %q", result.String())
+	}
+}
+
+func TestRendererWrapsToWidth(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	renderer, err := NewRenderer(font, Options{Width: 40})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	result, err := renderer.Render("Hello World")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	rows := result.Canvas.Height()
+	if rows < 2*font.Height || rows%font.Height != 0 {
+		t.Errorf("Expected wrapped output in blocks of %d rows, got %d rows", font.Height, rows)
+	}
+}
+
+func TestRendererOptionsOverrideFont(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	renderer, err := NewRenderer(font, Options{Layout: LayoutSmush, Fallback: FallbackError})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	if renderer.Font().Layout.Mode != LayoutSmush || renderer.Font().Fallback != FallbackError {
+		t.Errorf("Options not applied to font: %+v", renderer.Font())
+	}
+	if font.Layout.Mode != LayoutFull {
+		t.Error("NewRenderer() should not modify the original font")
+	}
+
+	_, err = renderer.Render("naïve")
+	var missing *MissingGlyphError
+	if !errors.As(err, &missing) || missing.Char != 'ï' {
+		t.Errorf("Render() error = %v, want missing glyph ï", err)
+	}
+}
+
+func TestFontMetrics(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	if font.Name != "standard" {
+		t.Errorf("Name = %q, want %q", font.Name, "standard")
+	}
+	if !font.Has('A') || font.Has('é') {
+		t.Error("Has() reports wrong coverage")
+	}
+	if glyph, ok := font.Glyph('A'); !ok || len(glyph) != font.Height {
+		t.Errorf("Glyph('A') = %q, %v", glyph, ok)
+	}
+	if got := font.GlyphWidth('A'); got != len(font.Glyphs['A'][0]) {
+		t.Errorf("GlyphWidth('A') = %d, want %d", got, len(font.Glyphs['A'][0]))
+	}
+	if got := font.GlyphWidth('é'); got != 0 {
+		t.Errorf("GlyphWidth('é') = %d, want 0", got)
+	}
+	if got, want := font.TextWidth("AB"), font.GlyphWidth('A')+font.GlyphWidth('B'); got != want {
+		t.Errorf("TextWidth(\"AB\") = %d, want %d", got, want)
+	}
+
+	runes := font.Runes()
+	if len(runes) != 95 || runes[0] != ' ' || runes[len(runes)-1] != '~' {
+		t.Errorf("Runes() = %d runes from %q to %q", len(runes), runes[0], runes[len(runes)-1])
+	}
+
+	custom := NewFont("mini", map[rune][]string{'x': {"x", "x"}})
+	if custom.Name != "mini" || custom.Height != 2 {
+		t.Errorf("NewFont() = %+v", custom)
+	}
+}
+
+func TestRendererOutputModes(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	render := func(options Options, text string) []string {
+		t.Helper()
+		renderer, err := NewRenderer(font, options)
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render(text)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		return result.Rows()
+	}
+
+	// Without a $ terminator no column is reserved for it, so rows are
+	// aligned like dollar rows one column wider
+	width := TerminalWidth()
+	legacy := strings.Split(renderArt(t, "Hi\\n\\nyou", font, "", "", "center"), "\n")
+	wider := render(Options{Alignment: "center", Output: OutputDollar, Width: width + 1}, "Hi\\n\\nyou")
+	for _, mode := range []string{OutputDollar, OutputNone, OutputTrim} {
+		rows := render(Options{Alignment: "center", Output: mode, Width: width}, "Hi\\n\\nyou")
+		if len(rows) != len(legacy) {
+			t.Fatalf("Mode %q rendered %d rows, want %d", mode, len(rows), len(legacy))
+		}
+		for i := range rows {
+			want := legacy[i]
+			switch mode {
+			case OutputNone:
+				want = strings.TrimSuffix(wider[i], "$")
+			case OutputTrim:
+				want = strings.TrimRight(strings.TrimSuffix(wider[i], "$"), " ")
+			}
+			if rows[i] != want {
+				t.Errorf("Mode %q row %d = %q, want %q", mode, i, rows[i], want)
+			}
+		}
+	}
+
+	// Right-aligned rows end at the last column, with or without a terminator;
+	// trimmed rows only lose their trailing spaces
+	none := render(Options{Alignment: "right", Output: OutputNone, Width: 40}, "ab")
+	trimmed := render(Options{Alignment: "right", Output: OutputTrim, Width: 40}, "ab")
+	for i, row := range render(Options{Alignment: "right", Output: OutputDollar, Width: 40}, "ab") {
+		if len(row) != 40 || len(none[i]) != 40 {
+			t.Errorf("row %d is %d columns wide in dollar mode and %d without a terminator, want 40", i, len(row), len(none[i]))
+		}
+		if want := strings.TrimRight(none[i], " "); trimmed[i] != want {
+			t.Errorf("trimmed row %d = %q, want %q", i, trimmed[i], want)
+		}
+	}
+}
+
+func TestRendererMultiByteWithWrappingAndAlignment(t *testing.T) {
+	font := fontFromMap(map[rune][]string{
+		'a': {"aa", "aa"},
+		'é': {"é_", "_é"},
+		' ': {" ", " "},
+	})
+	text := `aé\naéaé aéaé aé`
+	chars := []rune(text)
+	red := Color{Type: ColorBasic, Index: 1}
+
+	for _, alignment := range []string{"left", "right", "center", "justify"} {
+		t.Run(alignment, func(t *testing.T) {
+			renderer, err := NewRenderer(font, Options{Color: "red", Substring: "é", Alignment: alignment, Width: 12})
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+			result, err := renderer.Render(text)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result.Canvas.Height() < 3*font.Height {
+				t.Fatalf("expected the second line to wrap, got %d rows", result.Canvas.Height())
+			}
+
+			encoded := strings.Split(result.Encode(ANSIEncoder{}), "\n")
+			for y, row := range result.Canvas.Rows {
+				for x, cell := range row {
+					if cell.Source < 0 {
+						continue
+					}
+					source := chars[cell.Source]
+					if !strings.ContainsRune(strings.Join(font.Glyphs[source], ""), cell.Rune) {
+						t.Errorf("row %d column %d: %q is not part of the glyph of %q", y, x, cell.Rune, source)
+					}
+					if colored := cell.Style.Foreground == red; colored != (source == 'é') {
+						t.Errorf("row %d column %d: glyph of %q colored = %v", y, x, source, colored)
+					}
+				}
+				if got := getVisualLength(encoded[y]); got != len(row) {
+					t.Errorf("row %d is %d columns wide when encoded, want %d", y, got, len(row))
+				}
+				if alignment == "right" && len(row) > 0 && len(row) != 12 {
+					t.Errorf("right-aligned row %d is %d columns wide, want 12", y, len(row))
+				}
+			}
+		})
+	}
+}
+
+func TestRendererWithoutWidth(t *testing.T) {
+	font := fontFromMap(map[rune][]string{
+		'a': {"aa", "aa"},
+		'b': {"bbb", "bbb"},
+		' ': {" ", " "},
+	})
+
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+	}{
+		{"does not wrap", Options{}, []string{"aaaa aaaa aaaa", "aaaa aaaa aaaa", "bbb", "bbb"}},
+		{"aligns right within the widest line", Options{Alignment: "right"}, []string{"aaaa aaaa aaaa", "aaaa aaaa aaaa", "           bbb", "           bbb"}},
+		{"centers within the widest line", Options{Alignment: "center"}, []string{"aaaa aaaa aaaa", "aaaa aaaa aaaa", "     bbb", "     bbb"}},
+		{"wraps to an explicit width", Options{Width: 12, Wrap: WrapGreedy}, []string{"aaaa aaaa", "aaaa aaaa", "aaaa", "aaaa", "bbb", "bbb"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := NewRenderer(font, tt.options)
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+			result, err := renderer.Render(`aa aa aa\nb`)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			var got []string
+			for i := 0; i < result.Canvas.Height(); i++ {
+				got = append(got, result.Canvas.Text(i))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
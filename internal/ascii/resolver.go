@@ -0,0 +1,223 @@
+package ascii
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"ascii-art/assets"
+)
+
+// ErrBannerNotFound is returned when no banner matches a name or path
+var ErrBannerNotFound = errors.New("banner not found")
+
+// FontPathEnv names the environment variable listing extra font directories,
+// separated like $PATH
+const FontPathEnv = "ASCII_ART_FONT_PATH"
+
+// Resolver locates banners given by name or by file path. An explicit path is
+// loaded as is; a name is looked up in each of Dirs in turn and then among the
+// Embedded banners.
+type Resolver struct {
+	Dirs     []string // user font directories, searched in order
+	Embedded fs.FS    // banners compiled into the binary; nil for none
+}
+
+// NewResolver creates a resolver that searches dirs before the stock banners
+// embedded in the binary
+func NewResolver(dirs ...string) *Resolver {
+	return &Resolver{Dirs: dirs, Embedded: assets.Banners}
+}
+
+// DefaultResolver creates a resolver that searches the user font directories
+// returned by FontPath before the embedded banners
+func DefaultResolver() *Resolver {
+	return NewResolver(FontPath()...)
+}
+
+// FontPath returns the user font directories in search order: every entry of
+// $ASCII_ART_FONT_PATH, then $XDG_DATA_HOME/ascii-art/fonts (with the XDG
+// default of ~/.local/share) and ~/.config/ascii-art/fonts
+func FontPath() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv(FontPathEnv)) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		dirs = append(dirs, filepath.Join(dataHome, "ascii-art", "fonts"))
+	} else if home != "" {
+		dirs = append(dirs, filepath.Join(home, ".local", "share", "ascii-art", "fonts"))
+	}
+	if home != "" {
+		dirs = append(dirs, filepath.Join(home, ".config", "ascii-art", "fonts"))
+	}
+	return dirs
+}
+
+// Resolve loads the banner given by name (e.g. "standard" or "big.flf") or by
+// file path (e.g. "./fonts/big.flf")
+func (r *Resolver) Resolve(banner string) (*Font, error) {
+	source, err := r.locate(banner, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.load(source)
+}
+
+// ResolveName loads the banner with the given name without ever treating it
+// as a path, which makes it safe for names supplied by remote clients
+func (r *Resolver) ResolveName(name string) (*Font, error) {
+	source, err := r.locate(name, true)
+	if err != nil {
+		return nil, err
+	}
+	return r.load(source)
+}
+
+// fontSource identifies the file a banner is loaded from
+type fontSource struct {
+	path     string // file path, or the file name within the embedded banners
+	embedded bool
+}
+
+// locate finds the file of a banner given by name or, unless nameOnly is
+// set, by path
+func (r *Resolver) locate(banner string, nameOnly bool) (fontSource, error) {
+	notFound := fmt.Errorf("%w: %q", ErrBannerNotFound, banner)
+
+	if !nameOnly && isBannerPath(banner) {
+		if !isFile(banner) {
+			return fontSource{}, notFound
+		}
+		return fontSource{path: banner}, nil
+	}
+
+	if banner == "" || strings.ContainsAny(banner, `/\`) {
+		return fontSource{}, notFound
+	}
+
+	candidates := bannerFileNames(banner)
+	for _, dir := range r.Dirs {
+		for _, candidate := range candidates {
+			if path := filepath.Join(dir, candidate); isFile(path) {
+				return fontSource{path: path}, nil
+			}
+		}
+	}
+
+	if r.Embedded != nil {
+		for _, candidate := range candidates {
+			if info, err := fs.Stat(r.Embedded, candidate); err == nil && !info.IsDir() {
+				return fontSource{path: candidate, embedded: true}, nil
+			}
+		}
+	}
+
+	return fontSource{}, notFound
+}
+
+// load reads the banner at source
+func (r *Resolver) load(source fontSource) (*Font, error) {
+	if source.embedded {
+		return LoadFontFS(r.Embedded, source.path)
+	}
+	return LoadFont(source.path)
+}
+
+// FontInfo describes a banner that a resolver can load by name
+type FontInfo struct {
+	Name     string // name that selects the banner
+	Path     string // file the banner is loaded from
+	Embedded bool   // whether the banner is built into the binary
+}
+
+// List returns the banners available by name, sorted by name. A banner in an
+// earlier directory hides banners of the same name found later, exactly as
+// ResolveName would pick it. Missing directories are skipped.
+func (r *Resolver) List() ([]FontInfo, error) {
+	seen := make(map[string]bool)
+	var fonts []FontInfo
+
+	// add records the banners among the file names of a directory
+	add := func(dir string, names []string, embedded bool) {
+		sort.Strings(names)
+		for _, ext := range bannerExtensions {
+			for _, fileName := range names {
+				name := strings.TrimSuffix(fileName, ext)
+				if filepath.Ext(fileName) != ext || seen[name] {
+					continue
+				}
+				seen[name] = true
+				fonts = append(fonts, FontInfo{Name: name, Path: filepath.Join(dir, fileName), Embedded: embedded})
+			}
+		}
+	}
+
+	for _, dir := range r.Dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read font directory: %w", err)
+		}
+		add(dir, fileNames(entries), false)
+	}
+
+	if r.Embedded != nil {
+		entries, err := fs.ReadDir(r.Embedded, ".")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded banners: %w", err)
+		}
+		add("", fileNames(entries), true)
+	}
+
+	sort.Slice(fonts, func(i, j int) bool { return fonts[i].Name < fonts[j].Name })
+	return fonts, nil
+}
+
+// fileNames returns the names of the files among entries
+func fileNames(entries []fs.DirEntry) []string {
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// isBannerPath reports whether banner refers to a file rather than a name: it
+// contains a directory, or carries a banner extension and exists
+func isBannerPath(banner string) bool {
+	if strings.ContainsAny(banner, `/\`) {
+		return true
+	}
+	return hasBannerExtension(banner) && isFile(banner)
+}
+
+// bannerFileNames returns the file names a banner name may be stored under
+func bannerFileNames(name string) []string {
+	if hasBannerExtension(name) {
+		return []string{name}
+	}
+	names := make([]string, len(bannerExtensions))
+	for i, ext := range bannerExtensions {
+		names[i] = name + ext
+	}
+	return names
+}
+
+// isFile reports whether path names an existing regular file
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,132 @@
+package ascii
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolverLookupOrder(t *testing.T) {
+	userDir := t.TempDir()
+	custom := "# height=1\n" + "x\n" + "user\n"
+	if err := os.WriteFile(filepath.Join(userDir, "shadow.txt"), []byte(custom), 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+	flf := filepath.Join(userDir, "test.flf")
+	if err := os.WriteFile(flf, []byte(buildTestFIGlet()), 0644); err != nil {
+		t.Fatalf("Failed to write font: %v", err)
+	}
+
+	resolver := NewResolver(userDir)
+
+	tests := []struct {
+		name       string
+		banner     string
+		wantHeight int
+		wantErr    error
+	}{
+		{"embedded default", "standard", 8, nil},
+		{"user directory shadows embedded", "shadow", 1, nil},
+		{"flf by name", "test", 2, nil},
+		{"flf with extension", "test.flf", 2, nil},
+		{"explicit path", flf, 2, nil},
+		{"missing name", "nonexistent", 0, ErrBannerNotFound},
+		{"missing path", filepath.Join(userDir, "missing.txt"), 0, ErrBannerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font, err := resolver.Resolve(tt.banner)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if font.Height != tt.wantHeight {
+				t.Errorf("Resolve() height = %d, want %d", font.Height, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestResolverEmbeddedBanners(t *testing.T) {
+	resolver := NewResolver()
+	for _, name := range []string{"standard", "shadow", "thinkertoy"} {
+		embedded, err := resolver.ResolveName(name)
+		if err != nil {
+			t.Fatalf("ResolveName(%q) error = %v", name, err)
+		}
+		onDisk, err := LoadFont(filepath.Join("../../assets", name+".txt"))
+		if err != nil {
+			t.Fatalf("LoadFont() error = %v", err)
+		}
+		if embedded.Name != name || len(embedded.Glyphs) != len(onDisk.Glyphs) {
+			t.Errorf("Embedded %q = %q with %d glyphs, want %d", name, embedded.Name, len(embedded.Glyphs), len(onDisk.Glyphs))
+		}
+	}
+}
+
+func TestResolveNameRejectsPaths(t *testing.T) {
+	resolver := &Resolver{Embedded: fstest.MapFS{"standard.txt": {Data: []byte("x\n")}}}
+	for _, name := range []string{"", "../standard", "assets/standard", `..\standard`} {
+		if _, err := resolver.ResolveName(name); !errors.Is(err, ErrBannerNotFound) {
+			t.Errorf("ResolveName(%q) error = %v, want ErrBannerNotFound", name, err)
+		}
+	}
+}
+
+func TestFontPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(FontPathEnv, strings.Join([]string{"/shared/fonts", "", "/more/fonts"}, string(os.PathListSeparator)))
+
+	t.Setenv("XDG_DATA_HOME", "/data")
+	want := []string{"/shared/fonts", "/more/fonts", "/data/ascii-art/fonts", filepath.Join(home, ".config", "ascii-art", "fonts")}
+	if got := FontPath(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FontPath() = %q, want %q", got, want)
+	}
+
+	t.Setenv("XDG_DATA_HOME", "")
+	want[2] = filepath.Join(home, ".local", "share", "ascii-art", "fonts")
+	if got := FontPath(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FontPath() without XDG_DATA_HOME = %q, want %q", got, want)
+	}
+}
+
+func TestResolverList(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	for _, file := range []string{
+		filepath.Join(first, "shadow.txt"),
+		filepath.Join(first, "notes.md"),
+		filepath.Join(second, "shadow.flf"),
+		filepath.Join(second, "big.flf"),
+	} {
+		if err := os.WriteFile(file, []byte("x\n"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	resolver := NewResolver(first, filepath.Join(first, "missing"), second)
+	fonts, err := resolver.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := []FontInfo{
+		{Name: "big", Path: filepath.Join(second, "big.flf")},
+		{Name: "shadow", Path: filepath.Join(first, "shadow.txt")},
+		{Name: "standard", Path: "standard.txt", Embedded: true},
+		{Name: "thinkertoy", Path: "thinkertoy.txt", Embedded: true},
+	}
+	if !reflect.DeepEqual(fonts, want) {
+		t.Errorf("List() = %+v, want %+v", fonts, want)
+	}
+}
@@ -0,0 +1,67 @@
+package ascii
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColorRule colors every occurrence of a pattern. A pattern between slashes,
+// like "/v[0-9]+/", is a regular expression; anything else is matched as
+// Options.Match says.
+type ColorRule struct {
+	Pattern string
+	Color   string // any color ParseColor accepts
+}
+
+// ParseColorRule parses a "color:pattern" rule such as "red:ERROR",
+// "#ff8800:WARN" or "ansi256:208:/v[0-9]+/". The color is the shortest
+// prefix before a colon that ParseColor accepts, so the pattern may itself
+// contain colons.
+func ParseColorRule(spec string) (ColorRule, error) {
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != ':' {
+			continue
+		}
+		if _, err := ParseColor(spec[:i]); err != nil {
+			continue
+		}
+		if i == len(spec)-1 {
+			return ColorRule{}, fmt.Errorf("color rule %q has an empty pattern", spec)
+		}
+		return ColorRule{Color: spec[:i], Pattern: spec[i+1:]}, nil
+	}
+	return ColorRule{}, fmt.Errorf("%w: color rule %q should be color:pattern", ErrInvalidColor, spec)
+}
+
+// colorRule is a ColorRule ready to match lines
+type colorRule struct {
+	style   Style
+	pattern matcher
+}
+
+// compileColorRule validates a rule and prepares it for matching with the
+// match mode and case sensitivity of the renderer
+func compileColorRule(rule ColorRule, mode string, ignoreCase bool) (colorRule, error) {
+	color, err := ParseColor(rule.Color)
+	if err != nil {
+		return colorRule{}, err
+	}
+	if rule.Pattern == "" {
+		return colorRule{}, fmt.Errorf("color rule for %q has an empty pattern", rule.Color)
+	}
+
+	pattern := rule.Pattern
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		pattern, mode = pattern[1:len(pattern)-1], MatchRegex
+	}
+	compiled, err := compileMatcher(pattern, mode, ignoreCase)
+	if err != nil {
+		return colorRule{}, err
+	}
+	return colorRule{style: Style{Foreground: color}, pattern: compiled}, nil
+}
+
+// matches returns the rune spans of chars the rule matches
+func (rule colorRule) matches(chars []rune) []span {
+	return rule.pattern.spans(chars)
+}
@@ -0,0 +1,155 @@
+package ascii
+
+import (
+	"errors"
+	"testing"
+
+	"ascii-art/assets"
+)
+
+func TestParseColorRule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    ColorRule
+		wantErr bool
+	}{
+		{"red:ERROR", ColorRule{Color: "red", Pattern: "ERROR"}, false},
+		{"#ff8800:WARN", ColorRule{Color: "#ff8800", Pattern: "WARN"}, false},
+		{"ansi256:208:/v[0-9]+/", ColorRule{Color: "ansi256:208", Pattern: "/v[0-9]+/"}, false},
+		{"green:a:b", ColorRule{Color: "green", Pattern: "a:b"}, false},
+		{"red:", ColorRule{}, true},
+		{"red", ColorRule{}, true},
+		{"nope:ERROR", ColorRule{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseColorRule(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseColorRule(%q) = %+v, want an error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseColorRule(%q) error = %v", tt.spec, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColorRule(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexpSpans(t *testing.T) {
+	rule, err := compileColorRule(ColorRule{Pattern: "/[0-9]+/", Color: "red"}, MatchLiteral, false)
+	if err != nil {
+		t.Fatalf("compileColorRule() error = %v", err)
+	}
+
+	// Multi-byte characters before a match must not shift its rune span
+	got := rule.matches([]rune("é€12x3"))
+	want := []span{{2, 4}, {5, 6}}
+	if len(got) != len(want) {
+		t.Fatalf("matches() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("matches()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := compileColorRule(ColorRule{Pattern: "/[/", Color: "red"}, MatchLiteral, false); err == nil {
+		t.Error("compileColorRule() should reject an invalid regular expression")
+	}
+	if _, err := compileColorRule(ColorRule{Pattern: "x", Color: "nope"}, MatchLiteral, false); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("compileColorRule() error = %v, want %v", err, ErrInvalidColor)
+	}
+}
+
+func TestRenderColorRules(t *testing.T) {
+	font, err := LoadFontFS(assets.Banners, "standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFontFS() error = %v", err)
+	}
+	red, _ := LookupColor("red")
+	green, _ := LookupColor("green")
+	blue, _ := LookupColor("blue")
+	yellow, _ := LookupColor("yellow")
+
+	tests := []struct {
+		name    string
+		text    string
+		options Options
+		want    []Color // foreground of the cells of each source rune
+	}{
+		{
+			name:    "several rules",
+			text:    "ERR OK",
+			options: Options{Rules: []ColorRule{{Pattern: "ERR", Color: "red"}, {Pattern: "OK", Color: "green"}}},
+			want:    []Color{red, red, red, {}, green, green},
+		},
+		{
+			name:    "later rule wins on overlap",
+			text:    "abc",
+			options: Options{Rules: []ColorRule{{Pattern: "ab", Color: "red"}, {Pattern: "/bc/", Color: "blue"}}},
+			want:    []Color{red, blue, blue},
+		},
+		{
+			name:    "rules override the base color",
+			text:    "abc",
+			options: Options{Color: "yellow", Rules: []ColorRule{{Pattern: "b", Color: "green"}}},
+			want:    []Color{yellow, green, yellow},
+		},
+		{
+			name:    "rules override the substring color",
+			text:    "abab",
+			options: Options{Color: "yellow", Substring: "ab", Rules: []ColorRule{{Pattern: "/b$/", Color: "green"}}},
+			want:    []Color{yellow, yellow, yellow, green},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.Width = 200
+			renderer, err := NewRenderer(font, tt.options)
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+			result, err := renderer.Render(tt.text)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			for _, row := range result.Canvas.Rows {
+				for _, cell := range row {
+					if cell.Source < 0 {
+						continue
+					}
+					if got := cell.Style.Foreground; got != tt.want[cell.Source] {
+						t.Fatalf("cell %q of rune %d = %+v, want %+v", cell.Rune, cell.Source, got, tt.want[cell.Source])
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("rules take precedence over palettes", func(t *testing.T) {
+		renderer, err := NewRenderer(font, Options{Palette: "#ff0000,#00ff00", Rules: []ColorRule{{Pattern: "b", Color: "blue"}}, Width: 200})
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render("abc")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		want := []Color{RGBColor(255, 0, 0), blue, RGBColor(0, 255, 0)}
+		for _, row := range result.Canvas.Rows {
+			for _, cell := range row {
+				if got := cell.Style.Foreground; got != want[cell.Source] {
+					t.Fatalf("cell %q of rune %d = %+v, want %+v", cell.Rune, cell.Source, got, want[cell.Source])
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package ascii
+
+// transliterationGroups maps an ASCII replacement to the characters it approximates
+var transliterationGroups = map[string]string{
+	// Latin-1 Supplement and Latin Extended-A
+	"A": "ÀÁÂÃÄÅĀĂĄ", "a": "àáâãäåāăą", "AE": "Æ", "ae": "æ",
+	"C": "ÇĆĈĊČ", "c": "çćĉċč¢", "D": "ÐĎĐ", "d": "ðďđ",
+	"E": "ÈÉÊËĒĔĖĘĚ", "e": "èéêëēĕėęě", "G": "ĜĞĠĢ", "g": "ĝğġģ",
+	"H": "ĤĦ", "h": "ĥħ", "I": "ÌÍÎÏĨĪĬĮİ", "i": "ìíîïĩīĭįı",
+	"IJ": "Ĳ", "ij": "ĳ", "J": "Ĵ", "j": "ĵ", "K": "Ķ", "k": "ķ",
+	"L": "ĹĻĽĿŁ", "l": "ĺļľŀł", "N": "ÑŃŅŇ", "n": "ñńņň",
+	"O": "ÒÓÔÕÖØŌŎŐ", "o": "òóôõöøōŏő", "OE": "Œ", "oe": "œ",
+	"R": "ŔŖŘ", "r": "ŕŗř", "S": "ŚŜŞŠ", "s": "śŝşšſ", "ss": "ß",
+	"T": "ŢŤŦ", "t": "ţťŧ", "TH": "Þ", "th": "þ",
+	"U": "ÙÚÛÜŨŪŬŮŰŲ", "u": "ùúûüũūŭůűų", "W": "Ŵ", "w": "ŵ",
+	"Y": "ÝŶŸ¥", "y": "ýÿŷ", "Z": "ŹŻŽ", "z": "źżž", "x": "×",
+
+	// Punctuation and symbols
+	"'": "‘’‚‛′", "\"": "“”„‟″", "-": "‐‑‒–—―─━", "...": "…", "*": "•·",
+	"!": "¡", "?": "¿", "<<": "«", ">>": "»", "<": "‹", ">": "›",
+	"(C)": "©", "(R)": "®", "TM": "™", "EUR": "€", "GBP": "£", "+-": "±",
+	"/": "÷", " ": "\u00a0\u2002\u2003\u2009", "|": "¦│┃║", "=": "═",
+	"+": "┌┐└┘├┤┬┴┼╔╗╚╝╠╣╦╩╬",
+}
+
+// greekTransliterations follows the ELOT 743 romanisation of the Greek alphabet
+var greekTransliterations = map[rune]string{
+	'Α': "A", 'Β': "V", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z", 'Η': "I", 'Θ': "TH",
+	'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M", 'Ν': "N", 'Ξ': "X", 'Ο': "O", 'Π': "P",
+	'Ρ': "R", 'Σ': "S", 'Τ': "T", 'Υ': "Y", 'Φ': "F", 'Χ': "CH", 'Ψ': "PS", 'Ω': "O",
+	'Ά': "A", 'Έ': "E", 'Ή': "I", 'Ί': "I", 'Ό': "O", 'Ύ': "Y", 'Ώ': "O",
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o", 'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y", 'ώ': "o",
+	'ϊ': "i", 'ϋ': "y", 'ΐ': "i", 'ΰ': "y",
+}
+
+// transliterations maps a character to its ASCII approximation
+var transliterations = buildTransliterations()
+
+// buildTransliterations flattens the transliteration tables into a single lookup map
+func buildTransliterations() map[rune]string {
+	table := make(map[rune]string)
+	for replacement, chars := range transliterationGroups {
+		for _, char := range chars {
+			table[char] = replacement
+		}
+	}
+	for char, replacement := range greekTransliterations {
+		table[char] = replacement
+	}
+	return table
+}
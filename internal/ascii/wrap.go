@@ -0,0 +1,261 @@
+package ascii
+
+import "unicode"
+
+// Wrap modes selecting how lines wider than the output are broken into rows
+const (
+	WrapGreedy   = "greedy"   // fill every row before starting the next one
+	WrapBalanced = "balanced" // use as few rows as greedy, of widths as even as possible
+	WrapNone     = "none"     // never wrap
+)
+
+// IsValidWrap reports whether mode names a supported wrap mode
+func IsValidWrap(mode string) bool {
+	switch mode {
+	case WrapGreedy, WrapBalanced, WrapNone:
+		return true
+	}
+	return false
+}
+
+// span is a half-open range [start, end) of rune indices within a line
+type span struct {
+	start, end int
+}
+
+// segment is a span of a line rendered on its own rows of glyphs
+type segment struct {
+	span
+	hyphen bool // the segment ends inside a word and takes a hyphen glyph
+}
+
+// segments splits a line into the spans rendered on separate rows of glyphs
+func (r *Renderer) segments(chars []rune, termWidth int) []segment {
+	maxWidth := termWidth - 2 // reserve room for the $ terminator
+	if maxWidth < 10 || r.wrapMode() == WrapNone {
+		// Terminal too narrow, don't wrap
+		return []segment{{span: span{0, len(chars)}}}
+	}
+	return wrapSegments(r.font, chars, maxWidth, r.options.Hyphenate, r.wrapMode())
+}
+
+// wrapMode returns the wrap mode of the renderer: none without a width or a
+// canvas, otherwise balanced unless set
+func (r *Renderer) wrapMode() string {
+	if r.options.Width == 0 && r.options.Canvas.IsZero() {
+		return WrapNone
+	}
+	if r.options.Wrap == "" {
+		return WrapBalanced
+	}
+	return r.options.Wrap
+}
+
+// wrapSegments breaks chars into segments no wider than limit in the given
+// wrap mode. Lines break at spaces, which are dropped, and after hyphens; a
+// word wider than limit is split between characters, ending with a hyphen
+// glyph if hyphenate is set. A line that fits is returned whole.
+func wrapSegments(font *Font, chars []rune, limit int, hyphenate bool, mode string) []segment {
+	whole := span{0, len(chars)}
+	if mode == WrapNone || spanWidth(font, chars, whole, false) <= limit {
+		return []segment{{span: whole}}
+	}
+
+	points := breakPoints(font, chars, limit, hyphenate)
+	width := func(i, j int) int {
+		start := 0
+		if i > 0 {
+			start = points[i-1].next
+		}
+		return spanWidth(font, chars, span{start, points[j-1].end}, points[j-1].hyphen)
+	}
+
+	var segments []segment
+	start := 0
+	for _, end := range breakLines(len(points), limit, width, mode) {
+		point := points[end-1]
+		segments = append(segments, segment{span: span{start, point.end}, hyphen: point.hyphen})
+		start = point.next
+	}
+	return segments
+}
+
+// breakPoint is a place where a line may break: one row ends at end and the
+// next starts at next, dropping the spaces in between
+type breakPoint struct {
+	end, next int
+	hyphen    bool // the break splits a word and the row takes a hyphen glyph
+}
+
+// breakPoints returns the places where a line may break, in order: before
+// spaces, after hyphens and, in words wider than limit, between characters.
+// The last point is the end of the line without its trailing spaces.
+func breakPoints(font *Font, chars []rune, limit int, hyphenate bool) []breakPoint {
+	var points []breakPoint
+	start := 0 // start of the current piece of the line
+
+	// addPiece ends the current piece, splitting it if it is too wide
+	addPiece := func(end, next int) {
+		for {
+			fit := fittingEnd(font, chars[:end], start, limit)
+			if fit == end {
+				break
+			}
+			split, hyphen := fit, false
+			if hyphenate {
+				split, hyphen = hyphenBreak(font, chars, start, fit, limit)
+			}
+			points = append(points, breakPoint{end: split, next: split, hyphen: hyphen})
+			start = split
+		}
+		points = append(points, breakPoint{end: end, next: next})
+		start = next
+	}
+
+	end := len(chars)
+	for end > 0 && unicode.IsSpace(chars[end-1]) {
+		end--
+	}
+	for i := 1; i < end; i++ {
+		switch {
+		case unicode.IsSpace(chars[i]) && !unicode.IsSpace(chars[i-1]):
+			next := i
+			for unicode.IsSpace(chars[next]) {
+				next++
+			}
+			addPiece(i, next)
+			i = next - 1
+		case chars[i-1] == '-' && !unicode.IsSpace(chars[i]):
+			addPiece(i, i)
+		}
+	}
+	addPiece(end, len(chars))
+	return points
+}
+
+// breakLines chooses where to break a sequence of n items into rows no wider
+// than limit, given the width of a row holding items i to j-1. It returns the
+// end of every row. Greedy mode fills each row in turn. Balanced mode uses as
+// few rows and then keeps the sum of the squared free space of the rows, the
+// last one included, as small as possible, like the Knuth-Plass algorithm
+// without stretching. An item wider than limit gets a row of its own.
+func breakLines(n, limit int, width func(i, j int) int, mode string) []int {
+	if n == 0 {
+		return nil
+	}
+	switch mode {
+	case WrapNone:
+		return []int{n}
+	case WrapGreedy:
+		var ends []int
+		for i := 0; i < n; {
+			j := i + 1
+			for j < n && width(i, j+1) <= limit {
+				j++
+			}
+			ends = append(ends, j)
+			i = j
+		}
+		return ends
+	}
+
+	// best[j] is the cheapest way to break the first j items into rows
+	type choice struct {
+		rows, badness, prev int
+	}
+	best := make([]choice, n+1)
+	for j := 1; j <= n; j++ {
+		best[j] = choice{rows: -1}
+		for i := j - 1; i >= 0; i-- {
+			w := width(i, j)
+			if w > limit && i < j-1 {
+				break // rows only get wider as they start earlier
+			}
+			slack := 0
+			if w < limit {
+				slack = limit - w
+			}
+			rows, badness := best[i].rows+1, best[i].badness+slack*slack
+			if best[j].rows < 0 || rows < best[j].rows || rows == best[j].rows && badness < best[j].badness {
+				best[j] = choice{rows: rows, badness: badness, prev: i}
+			}
+		}
+	}
+
+	ends := make([]int, best[n].rows)
+	for j, k := n, len(ends)-1; j > 0; j, k = best[j].prev, k-1 {
+		ends[k] = j
+	}
+	return ends
+}
+
+// fittingEnd returns the end of the longest span of chars from start that is
+// no wider than limit. The span holds at least one glyph, however wide.
+func fittingEnd(font *Font, chars []rune, start, limit int) int {
+	line := newGlyphLine(font.Height, font.Layout)
+	hasGlyph := false
+	for i := start; i < len(chars); i++ {
+		charLines, exists := font.glyph(chars[i])
+		if !exists {
+			continue
+		}
+		if hasGlyph && line.widthWith(charLines) > limit {
+			return i
+		}
+		line.add(charLines, 0)
+		hasGlyph = true
+	}
+	return len(chars)
+}
+
+// hyphenBreak returns the end of the longest span of chars from start that
+// fits within limit together with a hyphen glyph, reporting whether the
+// hyphen is used. Without room for it, or without a hyphen in the font, the
+// span ends at fit and takes no hyphen.
+func hyphenBreak(font *Font, chars []rune, start, fit, limit int) (int, bool) {
+	if _, exists := font.glyph('-'); !exists {
+		return fit, false
+	}
+	for end := fit; end > start; end-- {
+		if spanWidth(font, chars, span{start, end}, true) <= limit {
+			return end, true
+		}
+	}
+	return fit, false
+}
+
+// spanWidth returns the rendered width of chars[s.start:s.end], followed by a
+// hyphen glyph if hyphen is set
+func spanWidth(font *Font, chars []rune, s span, hyphen bool) int {
+	line := newGlyphLine(font.Height, font.Layout)
+	for _, char := range chars[s.start:s.end] {
+		if charLines, exists := font.glyph(char); exists {
+			line.add(charLines, 0)
+		}
+	}
+	if hyphen {
+		if charLines, exists := font.glyph('-'); exists {
+			return line.widthWith(charLines)
+		}
+	}
+	return line.width()
+}
+
+// wordSpans returns the spans of the whitespace-separated words of chars[s.start:s.end]
+func wordSpans(chars []rune, s span) []span {
+	var words []span
+	start := -1
+	for i := s.start; i < s.end; i++ {
+		switch {
+		case unicode.IsSpace(chars[i]) && start >= 0:
+			words = append(words, span{start, i})
+			start = -1
+		case !unicode.IsSpace(chars[i]) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, span{start, s.end})
+	}
+	return words
+}
@@ -0,0 +1,151 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+)
+
+// narrowFont returns a font drawing every character as itself, one column wide
+func narrowFont(chars string) *Font {
+	glyphs := make(map[rune][]string)
+	for _, char := range chars {
+		glyphs[char] = []string{string(char)}
+	}
+	return fontFromMap(glyphs)
+}
+
+func TestWrapSegments(t *testing.T) {
+	font := narrowFont("abcdefghijklmnopqrstuvwxyz -")
+
+	tests := []struct {
+		name      string
+		text      string
+		limit     int
+		hyphenate bool
+		mode      string
+		want      []string
+	}{
+		{"fits", "hello world", 20, false, WrapGreedy, []string{"hello world"}},
+		{"breaks at the space", "hello world", 8, false, WrapGreedy, []string{"hello", "world"}},
+		{"keeps words together", "hello big world", 9, false, WrapGreedy, []string{"hello big", "world"}},
+		{"breaks after hyphens", "well-known fact", 7, false, WrapGreedy, []string{"well-", "known", "fact"}},
+		{"drops runs of spaces", "ab   cd", 3, false, WrapGreedy, []string{"ab", "cd"}},
+		{"drops trailing spaces", "ab cd  ", 3, false, WrapGreedy, []string{"ab", "cd"}},
+		{"splits long words", "abcdefghij", 4, false, WrapGreedy, []string{"abcd", "efgh", "ij"}},
+		{"hyphenates long words", "abcdefghij", 4, true, WrapGreedy, []string{"abc-", "def-", "ghij"}},
+		{"splits only the long word", "ab abcdefgh", 4, true, WrapGreedy, []string{"ab", "abc-", "def-", "gh"}},
+		{"keeps leading spaces", "  ab", 10, false, WrapGreedy, []string{"  ab"}},
+		{"greedy fills the first row", "aa bb cc dd ee ff", 14, false, WrapGreedy, []string{"aa bb cc dd ee", "ff"}},
+		{"balanced evens out the rows", "aa bb cc dd ee ff", 14, false, WrapBalanced, []string{"aa bb cc", "dd ee ff"}},
+		{"balanced keeps the fewest rows", "aaaa bb cc d", 6, false, WrapBalanced, []string{"aaaa", "bb", "cc d"}},
+		{"balanced splits long words", "abcdefghij", 4, true, WrapBalanced, []string{"abc-", "def-", "ghij"}},
+		{"none never wraps", "aa bb cc dd ee ff", 5, false, WrapNone, []string{"aa bb cc dd ee ff"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chars := []rune(tt.text)
+			var got []string
+			for _, s := range wrapSegments(font, chars, tt.limit, tt.hyphenate, tt.mode) {
+				text := string(chars[s.start:s.end])
+				if s.hyphen {
+					text += "-"
+				}
+				got = append(got, text)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("wrapSegments(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("wrapSegments(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestRendererWrapsAtWords(t *testing.T) {
+	font, err := LoadFont("../../assets/standard.txt")
+	if err != nil {
+		t.Fatalf("LoadFont() error = %v", err)
+	}
+
+	render := func(text string, options Options) []string {
+		t.Helper()
+		renderer, err := NewRenderer(font, options)
+		if err != nil {
+			t.Fatalf("NewRenderer() error = %v", err)
+		}
+		result, err := renderer.Render(text)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		rows := make([]string, result.Canvas.Height())
+		for i := range rows {
+			rows[i] = result.Canvas.Text(i)
+		}
+		return rows
+	}
+
+	wrapped := render("Hello World", Options{Width: 50})
+	want := append(render("Hello", Options{Width: 50}), render("World", Options{Width: 50})...)
+	if len(wrapped) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(wrapped))
+	}
+	for i := range want {
+		if wrapped[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, wrapped[i], want[i])
+		}
+	}
+
+	// A word too wide for the terminal is split, with a hyphen on request
+	plain := render("Abracadabra", Options{Width: 40})
+	hyphenated := render("Abracadabra", Options{Width: 40, Hyphenate: true})
+	if len(plain) < 2*font.Height || len(hyphenated) < 2*font.Height {
+		t.Fatalf("expected the word to be split, got %d and %d rows", len(plain), len(hyphenated))
+	}
+	hyphen := render("-", Options{})
+	for i, glyphRow := range hyphen {
+		if row := hyphenated[i]; !strings.HasSuffix(row, glyphRow) {
+			t.Errorf("split row %d = %q, want it to end with the hyphen glyph %q", i, row, glyphRow)
+		}
+	}
+}
+
+func TestRendererJustifyUsesWrapMode(t *testing.T) {
+	font := narrowFont("abcdef ")
+
+	tests := []struct {
+		wrap string
+		want []string // words of every row
+	}{
+		{WrapGreedy, []string{"aa bb cc dd ee", "ff"}},
+		{WrapBalanced, []string{"aa bb cc", "dd ee ff"}},
+		{WrapNone, []string{"aa bb cc dd ee ff"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wrap, func(t *testing.T) {
+			renderer, err := NewRenderer(font, Options{Alignment: "justify", Width: 16, Wrap: tt.wrap})
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+			result, err := renderer.Render("aa bb cc dd ee ff")
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if result.Canvas.Height() != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", result.Canvas.Height(), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := strings.Join(strings.Fields(result.Canvas.Text(i)), " "); got != want {
+					t.Errorf("row %d has words %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -90,6 +92,8 @@ func TestMainEdgeCases(t *testing.T) {
 	}{
 		{"no arguments", []string{}, false},
 		{"invalid flag", []string{"color=red", "test"}, true},
+		{"negative width", []string{"--width=-1", "test"}, true},
+		{"non-numeric width", []string{"--width=wide", "test"}, true},
 	}
 
 	for _, tt := range tests {
@@ -109,4 +113,129 @@ func TestMainEdgeCases(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestWidth(t *testing.T) {
+	rows := func(args ...string) []string {
+		t.Helper()
+		cmd := exec.Command("go", append([]string{"run", "./cmd/ascii-art"}, args...)...)
+		cmd.Env = append(os.Environ(), "COLUMNS=40")
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		return strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	}
+
+	text := "Hello There World"
+	if got := rows("--width=0", text); len(got) != 8 {
+		t.Errorf("--width=0 should not wrap, got %d rows", len(got))
+	}
+	wrapped := rows("--width=60", text)
+	if len(wrapped) <= 8 {
+		t.Errorf("--width=60 should wrap, got %d rows", len(wrapped))
+	}
+	for _, row := range wrapped {
+		if len(row) > 60 {
+			t.Errorf("row %q is wider than 60 columns", row)
+		}
+	}
+	right := rows("--width=100", "--align=right", "Hi")
+	if len(right[0]) != 100 {
+		t.Errorf("right-aligned row is %d columns wide, want 100", len(right[0]))
+	}
+}
+
+func TestCanvas(t *testing.T) {
+	output, err := exec.Command("go", "run", "./cmd/ascii-art", "--canvas=60x20", "--valign=middle", "--padding=1,2", "--margin=1", "Hi").Output()
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	rows := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	if len(rows) != 20 {
+		t.Fatalf("--canvas=60x20 printed %d rows, want 20", len(rows))
+	}
+	for _, row := range rows {
+		if len(row) != 60 || !strings.HasSuffix(row, "$") {
+			t.Errorf("row %q should be 60 columns wide and end with $", row)
+		}
+	}
+	if strings.TrimSpace(strings.TrimSuffix(rows[9], "$")) == "" {
+		t.Errorf("expected the text in the middle rows, got %q", rows)
+	}
+}
+
+func TestFontList(t *testing.T) {
+	cmd := exec.Command("go", "run", "./cmd/ascii-art", "font", "list")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("font list failed: %v", err)
+	}
+
+	for _, name := range []string{"standard", "shadow", "thinkertoy"} {
+		if !strings.Contains(string(output), name) {
+			t.Errorf("Expected %s in font list, got: %s", name, string(output))
+		}
+	}
+}
+
+func TestFontLint(t *testing.T) {
+	output, err := exec.Command("go", "run", "./cmd/ascii-art", "font", "lint", "assets/standard.txt").Output()
+	if err != nil {
+		t.Fatalf("font lint of a valid banner failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "no problems found") {
+		t.Errorf("Expected clean lint result, got: %s", string(output))
+	}
+
+	broken := filepath.Join(t.TempDir(), "broken.txt")
+	if err := os.WriteFile(broken, []byte("\n _ \n| |\r\n"), 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+	output, err = exec.Command("go", "run", "./cmd/ascii-art", "font", "lint", broken).Output()
+	if err == nil {
+		t.Error("font lint of an invalid banner should fail")
+	}
+	if !strings.Contains(string(output), broken+":3: warning") || !strings.Contains(string(output), "[truncated]") {
+		t.Errorf("Expected diagnostics with line numbers, got: %s", string(output))
+	}
+}
+
+func TestColorMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		env       []string
+		wantColor string // expected escape sequence; empty for none
+	}{
+		{"pipe strips colors", []string{"--color=red", "Hi"}, nil, ""},
+		{"always keeps colors", []string{"--color=red", "--color-mode=always", "Hi"}, nil, "\033[31m"},
+		{"never strips colors", []string{"--color=red", "--color-mode=never", "Hi"}, []string{"FORCE_COLOR=1"}, ""},
+		{"force color", []string{"--color=red", "Hi"}, []string{"FORCE_COLOR=1"}, "\033[31m"},
+		{"downsample to 256 colors", []string{"--color=#ff8800", "--color-mode=always", "Hi"}, []string{"TERM=xterm-256color", "COLORTERM="}, "\033[38;5;208m"},
+		{"truecolor", []string{"--color=#ff8800", "--color-mode=always", "Hi"}, []string{"COLORTERM=truecolor"}, "\033[38;2;255;136;0m"},
+		{"background and attributes", []string{"--color=white", "--bg=blue", "--attr=bold,underline", "--color-mode=always", "Hi"}, nil, "\033[1;4;37;44m"},
+		{"background on a substring", []string{"--bg=blue", "--color-mode=always", "i", "Hi", "standard"}, nil, "\033[44m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("go", append([]string{"run", "./cmd/ascii-art"}, tt.args...)...)
+			cmd.Env = append(os.Environ(), "NO_COLOR=", "FORCE_COLOR=", "TERM=xterm")
+			cmd.Env = append(cmd.Env, tt.env...)
+			output, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("command failed: %v", err)
+			}
+
+			if tt.wantColor == "" {
+				if strings.Contains(string(output), "\033[") {
+					t.Errorf("Expected no escape sequences, got: %q", output)
+				}
+			} else if !strings.Contains(string(output), tt.wantColor) {
+				t.Errorf("Expected %q in output, got: %q", tt.wantColor, output)
+			}
+		})
+	}
+}